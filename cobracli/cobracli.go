@@ -0,0 +1,88 @@
+// Package cobracli integrates github.com/fraugster/cli with applications that
+// are built with github.com/spf13/cobra.
+//
+// Register the output flag once on the root command and print the results of
+// all sub commands via PrintResult:
+//
+//	root := &cobra.Command{Use: "my-app"}
+//	cobracli.AddOutputFlag(root)
+//
+//	root.AddCommand(&cobra.Command{
+//		Use: "list",
+//		RunE: func(cmd *cobra.Command, args []string) error {
+//			return cobracli.PrintResult(cmd, listItems())
+//		},
+//	})
+package cobracli
+
+import (
+	"strings"
+
+	"github.com/fraugster/cli"
+	"github.com/spf13/cobra"
+)
+
+// FlagName is the name of the persistent flag that is registered by
+// AddOutputFlag.
+const FlagName = "output"
+
+// AddOutputFlag registers the persistent "-o" and "--output" flag on the given
+// command so the user can select the encoding of the output of the command and
+// of all its sub commands. If the flag is not set, cli.Print uses its default
+// encoding (see cli.EncodingEnv). Unsupported encodings are rejected when the
+// flags are parsed and the supported encodings are offered by the shell
+// completion of cobra.
+func AddOutputFlag(cmd *cobra.Command) {
+	var encoding string
+	usage := "Output format. One of " + strings.Join(cli.Encodings(), "|")
+	cmd.PersistentFlags().VarP((*encodingValue)(&encoding), FlagName, "o", usage)
+	_ = cmd.RegisterFlagCompletionFunc(FlagName, completeEncodings)
+}
+
+// Encoding returns the encoding that was selected via the output flag of the
+// given command or its parents. If the flag was not set or not registered via
+// AddOutputFlag, the empty string is returned which makes cli.Print use its
+// default encoding.
+func Encoding(cmd *cobra.Command) string {
+	f := cmd.Flag(FlagName)
+	if f == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+// PrintResult prints the value to the output of the command using the encoding
+// that was selected via the output flag (see AddOutputFlag).
+func PrintResult(cmd *cobra.Command, value interface{}, opts ...cli.Option) error {
+	return cli.PrintWriter(Encoding(cmd), value, cmd.OutOrStdout(), opts...)
+}
+
+// completeEncodings is a cobra completion function for the output flag.
+func completeEncodings(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return cli.Encodings(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// encodingValue is a pflag.Value that only accepts encodings that are
+// supported by cli.Print.
+type encodingValue string
+
+// String implements the pflag.Value interface.
+func (v *encodingValue) String() string {
+	return string(*v)
+}
+
+// Set implements the pflag.Value interface.
+func (v *encodingValue) Set(s string) error {
+	encoding, err := cli.ParseEncoding(s)
+	if err != nil {
+		return err
+	}
+
+	*v = encodingValue(encoding)
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (v *encodingValue) Type() string {
+	return "string"
+}
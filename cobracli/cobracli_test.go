@@ -0,0 +1,80 @@
+package cobracli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintResult(t *testing.T) {
+	type item struct {
+		Name  string
+		Count int
+	}
+
+	cases := map[string]struct {
+		args     []string
+		expected string
+		err      string
+	}{
+		"table": {
+			args:     []string{"list", "-o", "table"},
+			expected: "NAME    COUNT\nfoo     42\n",
+		},
+		"piped": {
+			args:     []string{"list"},
+			expected: "[\n    {\n        \"Name\": \"foo\",\n        \"Count\": 42\n    }\n]\n",
+		},
+		"before sub command": {
+			args:     []string{"--output=csv", "list"},
+			expected: "NAME,COUNT\nfoo,42\n",
+		},
+		"invalid": {
+			args: []string{"list", "-o", "xml"},
+			err:  `invalid argument "xml" for "-o, --output" flag: invalid encoding "xml" (valid values are table, detail, json, yaml, csv or raw)`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			root := &cobra.Command{Use: "test", SilenceErrors: true, SilenceUsage: true}
+			AddOutputFlag(root)
+			root.AddCommand(&cobra.Command{
+				Use: "list",
+				RunE: func(cmd *cobra.Command, args []string) error {
+					return PrintResult(cmd, []item{{Name: "foo", Count: 42}})
+				},
+			})
+
+			out := new(bytes.Buffer)
+			root.SetOut(out)
+			root.SetErr(ioutil.Discard)
+			root.SetArgs(c.args)
+
+			err := root.Execute()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, out.String())
+		})
+	}
+}
+
+func TestCompleteEncodings(t *testing.T) {
+	root := &cobra.Command{Use: "test"}
+	AddOutputFlag(root)
+
+	f, ok := root.GetFlagCompletionFunc(FlagName)
+	require.True(t, ok)
+
+	values, directive := f(root, nil, "")
+	assert.Equal(t, []string{"table", "detail", "json", "yaml", "csv", "raw"}, values)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
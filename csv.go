@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"context"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// printCSV prints the value as comma separated values. The columns and cells
+// are determined like in the "table" encoding.
+func printCSV(v interface{}, w io.Writer, opts *options) error {
+	o := *opts
+	o.placeholder = ""
+	o.cellReplacer = nil
+
+	out := csv.NewWriter(w)
+	if header, rows, ok := marshalTable(v); ok {
+		if err := out.Write(header); err != nil {
+			return err
+		}
+		if err := out.WriteAll(rows); err != nil {
+			return err
+		}
+		return out.Error()
+	}
+
+	t, rows, isArray := tableRows(reflect.ValueOf(v))
+	if t.Kind() != reflect.Struct {
+		if !isArray {
+			return fmt.Errorf("cannot print type %T as csv (kind %v)", v, t.Kind())
+		}
+		for _, row := range rows {
+			if err := out.Write([]string{fmt.Sprint(row)}); err != nil {
+				return err
+			}
+		}
+		out.Flush()
+		return out.Error()
+	}
+
+	fields, err := tableFields(t, &o)
+	if err != nil {
+		return err
+	}
+
+	if err := sortTable(fields, rows, &o); err != nil {
+		return err
+	}
+
+	fields, err = selectColumns(fields, rows, &o)
+	if err != nil {
+		return err
+	}
+
+	record := make([]string, len(fields))
+	for i, f := range fields {
+		record[i] = f.Column()
+	}
+	if err := out.Write(record); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		for i, f := range fields {
+			record[i] = formatCell(f.value(row), f, &o)
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// ReadCSV reads comma separated values from stdin and returns each record in a
+// channel. Records may span multiple lines if they contain quoted newlines.
+// Errors are reported like ReadLinesErr does. A record that is not received
+// when the context is closed is lost.
+func ReadCSV(ctx context.Context) (<-chan []string, <-chan error) {
+	return readCSV(ctx, stdinLineReader())
+}
+
+// ReadCSVInto reads comma separated values from stdin and stores them in the
+// slice of structs that v points to. The first record is the header. Its
+// columns are matched case insensitively against the column names and the
+// struct field names like in the "table" encoding, so values that were printed
+// as csv can be read back. Columns without a matching field are ignored.
+//
+// Empty cells leave the field at its zero value. Times are parsed using the
+// "format" tag option or as RFC 3339, booleans using the "bool" tag option if
+// present and lists are split at commas. Types that implement
+// encoding.TextUnmarshaler are supported as well.
+func ReadCSVInto(ctx context.Context, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice || baseType(rv.Elem().Type().Elem()).Kind() != reflect.Struct {
+		return fmt.Errorf("cannot read csv into %T: value must be a pointer to a slice of structs", v)
+	}
+
+	rowType := rv.Elem().Type().Elem()
+	fields, err := tableFields(baseType(rowType), newOptions(nil))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	records, errs := ReadCSV(ctx)
+	header, ok := <-records
+	if !ok {
+		return <-errs
+	}
+
+	columns := make([]*field, len(header))
+	for i, name := range header {
+		if j := fieldIndex(fields, name); j >= 0 {
+			columns[i] = &fields[j]
+		}
+	}
+
+	rows := reflect.MakeSlice(rv.Elem().Type(), 0, 0)
+	for record := range records {
+		row := reflect.New(rowType).Elem()
+		for i, cell := range record {
+			if columns[i] == nil || cell == "" {
+				continue
+			}
+			if err := parseCell(fieldByIndex(row, columns[i].Index), cell, *columns[i]); err != nil {
+				return fmt.Errorf("record %d: column %q: %v", rows.Len()+1, header[i], err)
+			}
+		}
+		rows = reflect.Append(rows, row)
+	}
+
+	if err := <-errs; err != nil {
+		return err
+	}
+
+	rv.Elem().Set(rows)
+	return nil
+}
+
+func readCSV(ctx context.Context, lr *lineReader) (<-chan []string, <-chan error) {
+	records := make(chan []string)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(records)
+		if err := lr.readCSV(ctx, records); err != nil {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	return records, errs
+}
+
+// readCSV sends the records that are read from lr to the given channel until
+// there are no more records, reading failed or the context is canceled.
+func (lr *lineReader) readCSV(ctx context.Context, records chan<- []string) error {
+	if err := lr.lock(ctx); err != nil {
+		return err
+	}
+	defer lr.unlock()
+
+	r := csv.NewReader(&csvInput{ctx: ctx, lr: lr})
+	for {
+		record, err := r.Read()
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		select {
+		case records <- record:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// csvInput is the io.Reader that the csv.Reader of readCSV reads from. It
+// returns at most one line per call, so the csv.Reader never buffers more
+// input than the record it is currently reading.
+type csvInput struct {
+	ctx  context.Context
+	lr   *lineReader
+	line string
+}
+
+func (in *csvInput) Read(p []byte) (int, error) {
+	if in.line == "" {
+		line, err := in.lr.next(in.ctx, '\n')
+		if err != nil {
+			return 0, err
+		}
+		in.line = line
+	}
+
+	n := copy(p, in.line)
+	in.line = in.line[n:]
+	return n, nil
+}
+
+// fieldByIndex returns the struct field of v with the given index sequence.
+// Unlike reflect.Value.FieldByIndex it allocates nil pointers to embedded or
+// flattened structs.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// parseCell parses the cell s of field f and stores the result in v.
+func parseCell(v reflect.Value, s string, f field) error {
+	if !v.CanSet() {
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	switch v.Type() {
+	case timeType:
+		layout := time.RFC3339
+		if f.Tag.Format != "" {
+			layout = f.Tag.Format
+		}
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		b, err := parseBool(s, f.Tag)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case isInt(v.Kind()):
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case isUint(v.Kind()):
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case isFloat(v.Kind()):
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Slice:
+		elems := strings.Split(s, ",")
+		list := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, elem := range elems {
+			if err := parseCell(list.Index(i), elem, f); err != nil {
+				return err
+			}
+		}
+		v.Set(list)
+	default:
+		return fmt.Errorf("cannot parse value of type %v", v.Type())
+	}
+
+	return nil
+}
+
+// parseBool parses a boolean that was formatted according to the "bool" tag
+// option.
+func parseBool(s string, tag tableTag) (bool, error) {
+	switch {
+	case tag.True != "" && s == tag.True:
+		return true, nil
+	case tag.False != "" && s == tag.False:
+		return false, nil
+	default:
+		return strconv.ParseBool(s)
+	}
+}
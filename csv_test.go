@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintCSV(t *testing.T) {
+	type row struct {
+		Name    string
+		Comment string
+		Token   string `table:",redact"`
+		Owner   *string
+	}
+
+	rows := []row{
+		{Name: "Foo", Comment: "a, \"quoted\"\nmessage", Token: "secret"},
+		{Name: "Bar"},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("csv", rows, out, SortBy("name", false)))
+	assert.Equal(t, "NAME,COMMENT,TOKEN,OWNER\nBar,,,\nFoo,\"a, \"\"quoted\"\"\nmessage\",****,\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("csv", []string{"a", "b"}, out))
+	assert.Equal(t, "a\nb\n", out.String())
+
+	err := PrintWriter("csv", 42, out)
+	assert.EqualError(t, err, "cannot print type int as csv (kind int)")
+}
+
+func TestReadCSV(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("name,comment\r\nfoo,\"multi\nline\"\nbar,\n")
+
+	records, errs := ReadCSV(context.Background())
+	var all [][]string
+	for record := range records {
+		all = append(all, record)
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, [][]string{{"name", "comment"}, {"foo", "multi\nline"}, {"bar", ""}}, all)
+
+	stdin = strings.NewReader("a,b\nc\n")
+	records, errs = ReadCSV(context.Background())
+	assert.Equal(t, []string{"a", "b"}, <-records)
+	_, ok := <-records
+	assert.False(t, ok)
+	assert.EqualError(t, <-errs, "record on line 2: wrong number of fields")
+}
+
+func TestReadCSVInto(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+
+	type owner struct {
+		Name string
+	}
+	type row struct {
+		Name    string
+		Size    int
+		Ratio   float64
+		Active  bool      `table:",bool=yes/no"`
+		Created time.Time `table:",format=2006-01-02"`
+		Timeout time.Duration
+		Tags    []string
+		Parent  *string
+		Owner   *owner `table:",flatten"`
+	}
+
+	stdin = strings.NewReader("NAME,SIZE,RATIO,ACTIVE,CREATED,TIMEOUT,TAGS,PARENT,OWNER.NAME,UNKNOWN\n" +
+		"foo,3,0.5,yes,2021-03-04,1m30s,\"a,b\",p,alice,x\n" +
+		"bar,,,no,,,,,,\n")
+
+	var rows []row
+	require.NoError(t, ReadCSVInto(context.Background(), &rows))
+	parent := "p"
+	assert.Equal(t, []row{
+		{
+			Name:    "foo",
+			Size:    3,
+			Ratio:   0.5,
+			Active:  true,
+			Created: time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+			Timeout: 90 * time.Second,
+			Tags:    []string{"a", "b"},
+			Parent:  &parent,
+			Owner:   &owner{Name: "alice"},
+		},
+		{Name: "bar"},
+	}, rows)
+
+	stdin = strings.NewReader("name,size\nfoo,big\n")
+	err := ReadCSVInto(context.Background(), &rows)
+	assert.EqualError(t, err, `record 1: column "size": strconv.ParseInt: parsing "big": invalid syntax`)
+
+	err = ReadCSVInto(context.Background(), &parent)
+	assert.EqualError(t, err, "cannot read csv into *string: value must be a pointer to a slice of structs")
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// printDetail prints each field of a struct on its own line as "NAME: value".
+// If v is a slice or an array, the records are separated by an empty line.
+// Values that are not structs are printed like the "table" encoding does.
+func printDetail(v interface{}, w io.Writer, opts *options) error {
+	if header, rows, ok := marshalTable(v); ok {
+		return printDetailMarshaler(header, rows, w, opts)
+	}
+
+	t, rows, _ := tableRows(reflect.ValueOf(v))
+	if t.Kind() != reflect.Struct || len(rows) == 0 && opts.noResults != "" {
+		return printTable(v, w, opts)
+	}
+
+	fields, err := tableFields(t, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := sortTable(fields, rows, opts); err != nil {
+		return err
+	}
+
+	fields, err = selectColumns(fields, rows, opts)
+	if err != nil {
+		return err
+	}
+
+	rows, more := limitRows(rows, opts)
+	buf := new(bytes.Buffer)
+	if err := writeTitle(buf, opts.useColor(w), opts); err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		writeDetail(buf, fields, row, opts.useColor(w), opts)
+	}
+
+	if more > 0 {
+		buf.WriteString("\n")
+	}
+
+	if _, err = w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	return writeLimitMessage(w, more, opts)
+}
+
+// printDetailStream prints all values that are received from the channel ch
+// like printDetail does. Each value is printed as soon as it is received.
+func printDetailStream(ch interface{}, w io.Writer, opts *options) error {
+	c := reflect.ValueOf(ch)
+	t := baseType(c.Type().Elem())
+	if t.Kind() != reflect.Struct {
+		return printTableStream(ch, w, opts)
+	}
+
+	fields, err := tableFields(t, opts)
+	if err != nil {
+		return err
+	}
+
+	fields, err = selectColumns(fields, nil, opts)
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	for i := 0; ; i++ {
+		row, ok := c.Recv()
+		if !ok && i == 0 && opts.noResults != "" {
+			return writeNoResults(w, opts)
+		}
+		if !ok {
+			return nil
+		}
+
+		// The stream may be unbounded, so the omitted values are not
+		// received.
+		if opts.limit > 0 && i == opts.limit {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+			return writeLimitMessage(w, -1, opts)
+		}
+
+		buf.Reset()
+		if i == 0 {
+			if err := writeTitle(buf, opts.useColor(w), opts); err != nil {
+				return err
+			}
+		} else {
+			buf.WriteString("\n")
+		}
+		writeDetail(buf, fields, row, opts.useColor(w), opts)
+
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+}
+
+// printDetailMarshaler prints the header and rows of a TableMarshaler like
+// printDetail does.
+func printDetailMarshaler(header []string, rows [][]string, w io.Writer, opts *options) error {
+	var more int
+	if opts.limit > 0 && len(rows) > opts.limit {
+		rows, more = rows[:opts.limit], len(rows)-opts.limit
+	}
+
+	var width int
+	for _, name := range header {
+		if n := textWidth(name); n > width {
+			width = n
+		}
+	}
+
+	var headerStyle Style
+	if opts.useColor(w) {
+		headerStyle = opts.headerStyle
+	}
+
+	buf := new(bytes.Buffer)
+	if err := writeTitle(buf, opts.useColor(w), opts); err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		for j, name := range header {
+			value := row[j]
+			if value == "" {
+				value = opts.placeholder
+			} else if opts.cellReplacer != nil {
+				value = opts.cellReplacer.Replace(value)
+			}
+			buf.WriteString(headerStyle.apply(name+":") + strings.Repeat(" ", width-textWidth(name)+1) + value + "\n")
+		}
+	}
+
+	if more > 0 {
+		buf.WriteString("\n")
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	return writeLimitMessage(w, more, opts)
+}
+
+// writeDetail writes the given fields of a single row to buf.
+func writeDetail(buf *bytes.Buffer, fields []field, row reflect.Value, color bool, opts *options) {
+	var width int
+	for _, f := range fields {
+		if n := textWidth(f.Column()); n > width {
+			width = n
+		}
+	}
+
+	var headerStyle Style
+	if color {
+		headerStyle = opts.headerStyle
+	}
+
+	for _, f := range fields {
+		name := f.Column()
+		value := escapeCell(formatCell(f.value(row), f, opts), f, opts)
+
+		lines := []string{value}
+		if f.Tag.Wrap > 0 {
+			lines = wrap(value, f.Tag.Wrap)
+		}
+
+		buf.WriteString(headerStyle.apply(name+":") + strings.Repeat(" ", width-textWidth(name)+1))
+		buf.WriteString(lines[0] + "\n")
+
+		// Continuation lines are aligned with the first line of the value.
+		for _, line := range lines[1:] {
+			buf.WriteString(strings.Repeat(" ", width+2) + line + "\n")
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintDetail(t *testing.T) {
+	type someType struct {
+		Name        string
+		Age         int
+		Description string `table:"DESC,wrap=10"`
+		Secret      string `table:"-"`
+	}
+
+	cases := map[string]struct {
+		instance interface{}
+		opts     []Option
+		expected []string
+	}{
+		"struct": {
+			instance: someType{Name: "Test", Age: 42, Description: "a rather long text"},
+			expected: []string{
+				"NAME: Test",
+				"AGE:  42",
+				"DESC: a rather",
+				"      long text",
+			},
+		},
+		"slice": {
+			instance: []someType{
+				{Name: "Foo", Age: 1},
+				{Name: "Bar", Age: 2},
+			},
+			opts: []Option{Columns("name", "age"), SortBy("name", false)},
+			expected: []string{
+				"NAME: Bar",
+				"AGE:  2",
+				"",
+				"NAME: Foo",
+				"AGE:  1",
+			},
+		},
+		"limit": {
+			instance: []someType{
+				{Name: "Foo", Age: 1},
+				{Name: "Bar", Age: 2},
+			},
+			opts: []Option{Columns("name"), Limit(1)},
+			expected: []string{
+				"NAME: Foo",
+				"",
+				"... 1 more rows",
+			},
+		},
+		"slice of strings": {
+			instance: []string{"A", "B"},
+			expected: []string{"A", "B"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("detail", c.instance, out, c.opts...))
+			assert.Equal(t, strings.Join(c.expected, "\n")+"\n", out.String())
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// Markers which are printed in front of the rows of a diff.
+const (
+	diffAdded     = "+"
+	diffRemoved   = "-"
+	diffChanged   = "~"
+	diffUnchanged = " "
+)
+
+// PrintTableDiff prints the difference between two slices or arrays of the
+// same struct type as a table to the standard output. Rows are matched by the
+// value of their key column (see the DiffKey option). Rows that only exist in
+// newValue are prefixed with "+" and rows that only exist in oldValue are
+// prefixed with "-" and printed after all other rows. Rows whose cells differ
+// are prefixed with "~". If colors are enabled (see the Color option), added
+// rows are printed in green, removed rows in red and changed cells in yellow.
+//
+// All options of the "table" encoding except for footers, sorting and grouping
+// can be used to customize the output.
+func PrintTableDiff(oldValue, newValue interface{}, opts ...Option) error {
+	return PrintTableDiffWriter(oldValue, newValue, os.Stdout, opts...)
+}
+
+// PrintTableDiffWriter is like PrintTableDiff but lets the caller inject an
+// io.Writer.
+func PrintTableDiffWriter(oldValue, newValue interface{}, w io.Writer, opts ...Option) error {
+	o := newOptions(opts)
+
+	oldType, oldRows, oldArray := tableRows(reflect.ValueOf(oldValue))
+	newType, newRows, newArray := tableRows(reflect.ValueOf(newValue))
+	if !oldArray || !newArray || oldType != newType || newType.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot diff %T and %T: values must be slices or arrays of the same struct type", oldValue, newValue)
+	}
+
+	fields, err := tableFields(newType, o)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
+		return fmt.Errorf("cannot diff %T: type has no columns", newValue)
+	}
+
+	key := fields[0]
+	if o.diffKey != "" {
+		var ok bool
+		key, ok = findField(fields, o.diffKey)
+		if !ok {
+			return fmt.Errorf("unknown diff key column %q", o.diffKey)
+		}
+	}
+
+	fields, err = selectColumns(fields, append(append([]reflect.Value(nil), oldRows...), newRows...), o)
+	if err != nil {
+		return err
+	}
+
+	oldByKey := map[string]reflect.Value{}
+	for _, row := range oldRows {
+		oldByKey[formatCell(key.value(row), key, o)] = row
+	}
+
+	var (
+		rows    []reflect.Value
+		markers []string
+		changed [][]bool
+	)
+
+	seen := map[string]bool{}
+	for _, row := range newRows {
+		k := formatCell(key.value(row), key, o)
+		seen[k] = true
+
+		old, ok := oldByKey[k]
+		if !ok {
+			rows, markers, changed = append(rows, row), append(markers, diffAdded), append(changed, nil)
+			continue
+		}
+
+		marker, cells := diffUnchanged, make([]bool, len(fields))
+		for i, f := range fields {
+			if formatCell(f.value(old), f, o) != formatCell(f.value(row), f, o) {
+				marker, cells[i] = diffChanged, true
+			}
+		}
+		rows, markers, changed = append(rows, row), append(markers, marker), append(changed, cells)
+	}
+
+	for _, row := range oldRows {
+		if !seen[formatCell(key.value(row), key, o)] {
+			rows, markers, changed = append(rows, row), append(markers, diffRemoved), append(changed, nil)
+		}
+	}
+
+	layout := newTableLayout(w, fields, rows, nil, nil, o)
+	if len(layout.header) == 0 {
+		return fmt.Errorf("cannot diff %T: no columns left to print", newValue)
+	}
+
+	// The row number column has no field.
+	offset := len(layout.header) - len(fields)
+
+	layout.header[0] = diffUnchanged + " " + layout.header[0]
+	for i, record := range layout.records {
+		record[0] = markers[i] + " " + record[0]
+	}
+
+	if o.useColor(w) {
+		layout.styles = make([][]Style, len(rows))
+		for i := range rows {
+			layout.styles[i] = make([]Style, len(layout.header))
+			for j := range layout.header {
+				switch {
+				case markers[i] == diffAdded:
+					layout.styles[i][j] = Green
+				case markers[i] == diffRemoved:
+					layout.styles[i][j] = Red
+				case markers[i] == diffChanged && j >= offset && changed[i][j-offset]:
+					layout.styles[i][j] = Yellow
+				}
+			}
+		}
+	}
+
+	if err := writeTitle(w, o.useColor(w), o); err != nil {
+		return err
+	}
+	return layout.write(w)
+}
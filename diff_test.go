@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type diffRow struct {
+	Name     string
+	Replicas int
+	Image    string
+}
+
+func TestPrintTableDiff(t *testing.T) {
+	desired := []diffRow{
+		{Name: "api", Replicas: 3, Image: "api:v2"},
+		{Name: "web", Replicas: 2, Image: "web:v1"},
+		{Name: "worker", Replicas: 1, Image: "worker:v1"},
+	}
+	actual := []diffRow{
+		{Name: "api", Replicas: 3, Image: "api:v1"},
+		{Name: "web", Replicas: 2, Image: "web:v1"},
+		{Name: "cron", Replicas: 1, Image: "cron:v1"},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintTableDiffWriter(actual, desired, out))
+	expected := []string{
+		"  NAME    REPLICAS  IMAGE",
+		"~ api     3         api:v2",
+		"  web     2         web:v1",
+		"+ worker  1         worker:v1",
+		"- cron    1         cron:v1",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintTableDiffWriter(actual[:1], desired[:1], out, Color(ColorAlways), Columns("name", "image")))
+	expected = []string{
+		"  NAME  IMAGE",
+		"~ api   \x1b[33mapi:v2\x1b[0m",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTableDiff_Key(t *testing.T) {
+	type row struct {
+		ID   int
+		Name string
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintTableDiffWriter([]row{{1, "foo"}}, []row{{2, "foo"}}, out, DiffKey("name"), Columns("id")))
+	expected := []string{
+		"  ID",
+		"~ 2",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintTableDiffWriter([]row{}, []row{}, out, DiffKey("foo"))
+	assert.EqualError(t, err, `unknown diff key column "foo"`)
+
+	err = PrintTableDiffWriter([]row{}, []diffRow{}, out)
+	assert.EqualError(t, err, "cannot diff []cli.row and []cli.diffRow: values must be slices or arrays of the same struct type")
+}
+
+func TestPrintTableDiff_NoColumns(t *testing.T) {
+	out := new(bytes.Buffer)
+	err := PrintTableDiffWriter([]diffRow{{}}, []diffRow{{}}, out, HideEmptyColumns())
+	assert.EqualError(t, err, "cannot diff []cli.diffRow: no columns left to print")
+	assert.Empty(t, out.String())
+}
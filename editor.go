@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// defaultEditor returns the editor that is used if neither the VISUAL nor the
+// EDITOR environment variable is set.
+func defaultEditor() string {
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// Edit writes the initial content to a temporary file, opens it in the editor
+// of the user and returns the content of the file once the editor was closed.
+// The editor is taken from the VISUAL or EDITOR environment variables and
+// defaults to vi (notepad on Windows). The editor is killed if the context is
+// canceled before it was closed.
+func Edit(ctx context.Context, initial []byte) ([]byte, error) {
+	return edit(ctx, initial, "*.txt")
+}
+
+// edit implements Edit. The name of the temporary file is created from the
+// given pattern like os.CreateTemp does, so the extension of the file can be
+// used by the editor to pick a syntax highlighting.
+func edit(ctx context.Context, initial []byte, pattern string) ([]byte, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(initial)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	editor := strings.Fields(os.Getenv("VISUAL"))
+	if len(editor) == 0 {
+		editor = strings.Fields(os.Getenv("EDITOR"))
+	}
+	if len(editor) == 0 {
+		editor = []string{defaultEditor()}
+	}
+
+	cmd := exec.CommandContext(ctx, editor[0], append(editor[1:], f.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	return os.ReadFile(f.Name())
+}
+
+// EditStruct opens the value that v points to as YAML in the editor of the
+// user like Edit does and decodes the edited YAML back into v. If the edited
+// YAML is invalid or contains unknown fields, the editor is opened again with
+// the error at the top of the file, so the user can fix it. The value is only
+// changed once the YAML could be decoded.
+//
+// Use the EditComment option to explain the value at the top of the file and
+// the MaxAttempts option to limit how often the editor is opened again. If
+// the user removes all content from the file, the edit is canceled and an
+// error is returned.
+func EditStruct(ctx context.Context, v interface{}, opts ...PromptOption) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot edit %T: value must be a non-nil pointer", v)
+	}
+
+	doc, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	o := newPromptOptions(opts)
+	content := append(yamlComment(o.comment), doc...)
+	var banner []byte
+	for attempt := 1; ; attempt++ {
+		edited, err := edit(ctx, append(banner, content...), "*.yaml")
+		if err != nil {
+			return err
+		}
+
+		content = bytes.TrimPrefix(edited, banner)
+		if isEmptyYAML(content) {
+			return errors.New("edit canceled because the file is empty")
+		}
+
+		value := reflect.New(rv.Elem().Type())
+		invalid := yaml.UnmarshalStrict(content, value.Interface())
+		if invalid == nil {
+			rv.Elem().Set(value.Elem())
+			return nil
+		}
+
+		if err := o.retry(ctx, attempt, invalid); err != nil {
+			return err
+		}
+		banner = yamlComment("The edited file could not be decoded. Please fix the following error\nor remove all content to cancel the edit:\n\n" + invalid.Error() + "\n")
+	}
+}
+
+// yamlComment returns the given text as YAML comment.
+func yamlComment(text string) []byte {
+	if text == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		buf.WriteString(strings.TrimSpace("# "+line) + "\n")
+	}
+	return buf.Bytes()
+}
+
+// isEmptyYAML returns true if the YAML document only contains comments and
+// whitespace.
+func isEmptyYAML(doc []byte) bool {
+	for _, line := range strings.Split(string(doc), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
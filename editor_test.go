@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdit(t *testing.T) {
+	defer os.Setenv("VISUAL", os.Getenv("VISUAL"))
+	defer os.Setenv("EDITOR", os.Getenv("EDITOR"))
+
+	require.NoError(t, os.Setenv("VISUAL", ""))
+	require.NoError(t, os.Setenv("EDITOR", "sed -i s/foo/bar/"))
+	out, err := Edit(context.Background(), []byte("foo\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bar\n", string(out))
+
+	require.NoError(t, os.Setenv("VISUAL", "sed -i s/foo/baz/"))
+	out, err = Edit(context.Background(), []byte("foo\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "baz\n", string(out))
+
+	require.NoError(t, os.Setenv("VISUAL", "false"))
+	_, err = Edit(context.Background(), nil)
+	assert.EqualError(t, err, "exit status 1")
+}
+
+func TestEdit_Canceled(t *testing.T) {
+	defer os.Setenv("VISUAL", os.Getenv("VISUAL"))
+	require.NoError(t, os.Setenv("VISUAL", "tail -f"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := Edit(ctx, nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestEditStruct(t *testing.T) {
+	defer os.Setenv("VISUAL", os.Getenv("VISUAL"))
+	out := mockPrompt(t, "")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+
+	// The editor makes the file invalid the first time it is opened and
+	// fixes it once the error is shown.
+	dir := t.TempDir()
+	editor := filepath.Join(dir, "editor.sh")
+	require.NoError(t, os.WriteFile(editor, []byte(`#!/bin/sh
+cp "$1" `+filepath.Join(dir, "last.yaml")+`
+if grep -q "could not be decoded" "$1"; then
+	sed -i "s/size: big/size: 3/" "$1"
+else
+	sed -i "s/size: 1/size: big/" "$1"
+fi
+`), 0700))
+	require.NoError(t, os.Setenv("VISUAL", editor))
+
+	type item struct {
+		Name string `yaml:"name"`
+		Size int    `yaml:"size"`
+	}
+	v := item{Name: "foo", Size: 1}
+
+	require.NoError(t, EditStruct(context.Background(), &v, EditComment("Edit the item.")))
+	assert.Equal(t, item{Name: "foo", Size: 3}, v)
+	assert.Equal(t, "✗ yaml: unmarshal errors:\n  line 3: cannot unmarshal !!str `big` into int\n", out.String())
+
+	last, err := os.ReadFile(filepath.Join(dir, "last.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "# The edited file could not be decoded. Please fix the following error\n"+
+		"# or remove all content to cancel the edit:\n#\n"+
+		"# yaml: unmarshal errors:\n#   line 3: cannot unmarshal !!str `big` into int\n"+
+		"# Edit the item.\nname: foo\nsize: big\n", string(last))
+
+	assert.EqualError(t, EditStruct(context.Background(), v), "cannot edit cli.item: value must be a non-nil pointer")
+}
+
+func TestEditStruct_Empty(t *testing.T) {
+	defer os.Setenv("VISUAL", os.Getenv("VISUAL"))
+	require.NoError(t, os.Setenv("VISUAL", "sed -i /name/d"))
+
+	v := map[string]string{"name": "foo"}
+	err := EditStruct(context.Background(), &v, EditComment("Remove all lines to cancel."))
+	assert.EqualError(t, err, "edit canceled because the file is empty")
+	assert.Equal(t, map[string]string{"name": "foo"}, v)
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Error is an error that carries the exit code of the process. It can be
+// returned by the commands of an application so the exit code does not have to
+// be determined by inspecting the error. Use PrintError to print the error and
+// to obtain its exit code.
+type Error struct {
+	// Code is the exit code of the process. If it is zero, the exit code is 1.
+	Code int `json:"code" yaml:"code"`
+
+	// Msg is the human readable error message.
+	Msg string `json:"error" yaml:"error"`
+
+	// Details is an optional value that contains additional information
+	// about the error (e.g. a struct or a slice of validation errors). It is
+	// printed like any other value that is passed to Print.
+	Details interface{} `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// NewError returns a new *Error with the given exit code and a message that is
+// formatted according to a format specifier.
+func NewError(code int, format string, a ...interface{}) *Error {
+	return &Error{Code: code, Msg: fmt.Sprintf(format, a...)}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+// ExitCode returns the exit code of the process.
+func (e *Error) ExitCode() int {
+	if e.Code == 0 {
+		return 1
+	}
+	return e.Code
+}
+
+// PrintError prints the error to the standard error and returns the exit code
+// that the process should use. If err is nil nothing is printed and 0 is
+// returned. If err is or wraps an *Error, its exit code is returned. All other
+// errors have the exit code 1.
+//
+// The error is printed according to the encoding that was requested by the
+// user so automation can parse failures as well as results. If the "json" or
+// "yaml" encoding is used, the error is printed as an object with the "error",
+// "code" and "details" keys (e.g. {"error": "not found", "code": 1}). With all
+// other encodings the message is printed as "Error: message" on a single
+// line, followed by the details of an *Error which are printed using the same
+// encoding (or the "table" encoding if the encoding is unknown).
+//
+// Usually PrintError is called from the main function of an application:
+//
+//	if err := run(); err != nil {
+//		os.Exit(cli.PrintError(encoding, err))
+//	}
+func PrintError(encoding string, err error) int {
+	return PrintErrorWriter(encoding, err, stderr)
+}
+
+// PrintErrorWriter is like PrintError but lets the caller inject an io.Writer.
+func PrintErrorWriter(encoding string, err error, w io.Writer) int {
+	if err == nil {
+		return 0
+	}
+
+	out := Error{Code: 1, Msg: err.Error()}
+	var e *Error
+	if errors.As(err, &e) {
+		out.Code = e.ExitCode()
+		out.Details = e.Details
+	}
+
+	encoding = strings.ToLower(encoding)
+	if encoding == "" {
+		encoding = defaultEncoding(w)
+	}
+
+	switch encoding {
+	case "json", "yml", "yaml":
+		if PrintWriter(encoding, out, w) == nil {
+			return out.Code
+		}
+	case "table", "detail", "csv", "raw":
+	default:
+		encoding = "table"
+	}
+
+	fmt.Fprintf(w, "Error: %v\n", out.Msg)
+	if out.Details != nil {
+		if err := PrintWriter(encoding, out.Details, w); err != nil {
+			fmt.Fprintf(w, "Error: %v\n", err)
+		}
+	}
+
+	return out.Code
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintError(t *testing.T) {
+	type detail struct {
+		Field  string
+		Reason string
+	}
+
+	validationErr := &Error{
+		Code:    2,
+		Msg:     "invalid request",
+		Details: []detail{{Field: "name", Reason: "required"}},
+	}
+
+	cases := map[string]struct {
+		encoding string
+		err      error
+		code     int
+		expected string
+	}{
+		"nil": {
+			encoding: "table",
+			err:      nil,
+			code:     0,
+			expected: "",
+		},
+		"plain error": {
+			encoding: "table",
+			err:      errors.New("something went wrong"),
+			code:     1,
+			expected: "Error: something went wrong\n",
+		},
+		"plain error json": {
+			encoding: "json",
+			err:      errors.New("something went wrong"),
+			code:     1,
+			expected: "{\n    \"code\": 1,\n    \"error\": \"something went wrong\"\n}\n",
+		},
+		"wrapped json": {
+			encoding: "JSON",
+			err:      fmt.Errorf("failed to create user: %w", NewError(3, "user exists")),
+			code:     3,
+			expected: "{\n    \"code\": 3,\n    \"error\": \"failed to create user: user exists\"\n}\n",
+		},
+		"csv": {
+			encoding: "csv",
+			err:      validationErr,
+			code:     2,
+			expected: "Error: invalid request\nFIELD,REASON\nname,required\n",
+		},
+		"unknown encoding": {
+			encoding: "xml",
+			err:      &Error{Msg: "failed", Details: "try again"},
+			code:     1,
+			expected: "Error: failed\ntry again\n",
+		},
+		"table": {
+			encoding: "table",
+			err:      validationErr,
+			code:     2,
+			expected: "Error: invalid request\n" +
+				"FIELD   REASON\n" +
+				"name    required\n",
+		},
+		"wrapped": {
+			encoding: "table",
+			err:      fmt.Errorf("failed to create user: %w", NewError(3, "user %q exists", "alice")),
+			code:     3,
+			expected: "Error: failed to create user: user \"alice\" exists\n",
+		},
+		"default code": {
+			encoding: "table",
+			err:      &Error{Msg: "failed"},
+			code:     1,
+			expected: "Error: failed\n",
+		},
+		"json": {
+			encoding: "json",
+			err:      validationErr,
+			code:     2,
+			expected: `{
+    "code": 2,
+    "error": "invalid request",
+    "details": [
+        {
+            "Field": "name",
+            "Reason": "required"
+        }
+    ]
+}
+`,
+		},
+		"yaml": {
+			encoding: "yaml",
+			err:      &Error{Msg: "failed"},
+			code:     1,
+			expected: "code: 1\nerror: failed\n\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			code := PrintErrorWriter(c.encoding, c.err, out)
+			assert.Equal(t, c.code, code)
+			assert.Equal(t, c.expected, out.String())
+		})
+	}
+}
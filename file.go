@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileEncodings contains the encodings of PrintFile by file extension.
+var fileEncodings = map[string]string{
+	".json": "json",
+	".yml":  "yaml",
+	".yaml": "yaml",
+	".csv":  "csv",
+}
+
+// PrintFile prints the value to the file at the given path. The encoding is
+// inferred from the file extension which must be one of ".json", ".yml",
+// ".yaml" or ".csv". If the path has an additional ".gz" extension (e.g.
+// "export.csv.gz"), the file is compressed using gzip. The value is first
+// written to a temporary file in the same directory which is then renamed to
+// path, so the file is never left partially written if an error occurs or the
+// application is interrupted. An existing file keeps its permissions.
+func PrintFile(path string, value interface{}, opts ...Option) error {
+	encoding, ok := fileEncoding(path)
+	if !ok {
+		return fmt.Errorf("cannot infer encoding of file %q", path)
+	}
+
+	return writeFile(path, encoding, value, opts)
+}
+
+// fileEncoding returns the encoding of the file at the given path by its
+// extension. A ".gz" extension is ignored.
+func fileEncoding(path string) (string, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".gz" {
+		ext = strings.ToLower(filepath.Ext(strings.TrimSuffix(path, filepath.Ext(path))))
+	}
+
+	encoding, ok := fileEncodings[ext]
+	return encoding, ok
+}
+
+// isGzip returns true if the file at the given path should be compressed.
+func isGzip(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".gz")
+}
+
+// writeFile prints the value to the file at the given path like PrintFile does
+// using the given encoding. The file is compressed if it has a ".gz" extension.
+func writeFile(path, encoding string, value interface{}, opts []Option) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	f, err := os.CreateTemp(dir, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	// Remove the temporary file if anything goes wrong. This is a no-op once
+	// it has been renamed.
+	defer os.Remove(f.Name())
+
+	var w io.Writer = f
+	var gz *gzip.Writer
+	if isGzip(path) {
+		gz = gzip.NewWriter(f)
+		w = gz
+	}
+
+	if err := PrintWriter(encoding, value, w, opts...); err != nil {
+		f.Close()
+		return err
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(f.Name(), mode); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// printTee prints the value to w and to the file at the given path. The
+// encoding of the file is inferred from its extension like PrintFile does and
+// defaults to the given encoding.
+func printTee(encoding string, value interface{}, w io.Writer, path string, opts []Option) error {
+	if isStream(value) {
+		value = collectStream(value)
+	}
+
+	opts = append(opts[:len(opts):len(opts)], Tee(""))
+	if err := PrintWriter(encoding, value, w, opts...); err != nil {
+		return err
+	}
+
+	if e, ok := fileEncoding(path); ok {
+		encoding = e
+	}
+	return writeFile(path, encoding, value, append(opts, Color(ColorNever)))
+}
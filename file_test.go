@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintFile(t *testing.T) {
+	dir := t.TempDir()
+	value := []struct {
+		Name string `json:"name" yaml:"name"`
+	}{{Name: "Foo"}}
+
+	cases := map[string]string{
+		"out.json": "[\n    {\n        \"name\": \"Foo\"\n    }\n]\n",
+		"out.YAML": "- name: Foo\n\n",
+		"out.csv":  "NAME\nFoo\n",
+	}
+
+	for name, expected := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			require.NoError(t, PrintFile(path, value))
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(content))
+		})
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, len(cases), "temporary files must be removed")
+}
+
+func TestPrintFile_Gzip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "export.CSV.gz")
+	value := []struct{ Name string }{{Name: "Foo"}}
+	require.NoError(t, PrintFile(path, value))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "NAME\nFoo\n", string(content))
+
+	err = PrintFile(filepath.Join(dir, "export.gz"), value)
+	assert.EqualError(t, err, `cannot infer encoding of file "`+filepath.Join(dir, "export.gz")+`"`)
+}
+
+func TestPrintFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.json")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0600))
+
+	err := PrintFile(filepath.Join(dir, "out.txt"), "foo")
+	assert.EqualError(t, err, `cannot infer encoding of file "`+filepath.Join(dir, "out.txt")+`"`)
+
+	// The existing file must not be touched if the value cannot be encoded.
+	err = PrintFile(path, func() {})
+	assert.Error(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(content))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, PrintFile(path, "new"))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestPrintWriter_Tee(t *testing.T) {
+	dir := t.TempDir()
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	cases := map[string]struct {
+		file     string
+		value    interface{}
+		expected string
+	}{
+		"json": {
+			file:     "users.json",
+			value:    []user{{Name: "alice"}},
+			expected: "[\n    {\n        \"name\": \"alice\"\n    }\n]\n",
+		},
+		"same encoding": {
+			file:     "users.txt",
+			value:    []user{{Name: "alice"}},
+			expected: "NAME\nalice\n",
+		},
+		"stream": {
+			file: "stream.csv",
+			value: func() <-chan user {
+				c := make(chan user, 1)
+				c <- user{Name: "alice"}
+				close(c)
+				return c
+			}(),
+			expected: "NAME\nalice\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, c.file)
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("table", c.value, out, Tee(path)))
+			assert.Equal(t, "NAME\nalice\n", out.String())
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, string(content))
+		})
+	}
+
+	err := PrintWriter("table", []user{}, new(bytes.Buffer), Tee(filepath.Join(dir, "missing", "users.json")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// encodings contains the names of all encodings that are supported by Print.
+// The "yml" alias of "yaml" is accepted as well.
+var encodings = []string{"table", "detail", "json", "yaml", "csv", "raw"}
+
+// OutputFlag defines the "-o" and "--output" flags on the given flag set which
+// let the user select the encoding that is passed to Print. Both flags share
+// the returned value. It is empty if the flags are not set so Print uses its
+// default encoding (see EncodingEnv). Unsupported encodings are rejected when
+// the flags are parsed with an error that lists all valid values. If fs is
+// nil, the flags are defined on flag.CommandLine.
+func OutputFlag(fs *flag.FlagSet) *string {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+
+	var encoding string
+	value := (*encodingValue)(&encoding)
+	usage := "Output format. One of " + strings.Join(encodings, "|")
+	fs.Var(value, "output", usage)
+	fs.Var(value, "o", usage+" (shorthand)")
+	return &encoding
+}
+
+// encodingValue is a flag.Value that only accepts supported encodings.
+type encodingValue string
+
+// String implements the flag.Value interface.
+func (v *encodingValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return string(*v)
+}
+
+// Set implements the flag.Value interface.
+func (v *encodingValue) Set(s string) error {
+	encoding, err := ParseEncoding(s)
+	if err != nil {
+		return err
+	}
+
+	*v = encodingValue(encoding)
+	return nil
+}
+
+// Encodings returns the names of all encodings that are supported by Print.
+func Encodings() []string {
+	return append([]string(nil), encodings...)
+}
+
+// ParseEncoding returns the given encoding in lower case or an error that lists
+// all valid values if the encoding is not supported by Print. This is useful to
+// validate the encoding before an application starts to do its actual work.
+func ParseEncoding(s string) (string, error) {
+	s = strings.ToLower(s)
+	if s == "yml" {
+		return s, nil
+	}
+
+	for _, encoding := range encodings {
+		if s == encoding {
+			return s, nil
+		}
+	}
+
+	valid := strings.Join(encodings[:len(encodings)-1], ", ") + " or " + encodings[len(encodings)-1]
+	return "", fmt.Errorf("invalid encoding %q (valid values are %s)", s, valid)
+}
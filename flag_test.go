@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputFlag(t *testing.T) {
+	cases := map[string]struct {
+		args     []string
+		expected string
+		err      string
+	}{
+		"default": {
+			args:     nil,
+			expected: "",
+		},
+		"long": {
+			args:     []string{"--output", "json"},
+			expected: "json",
+		},
+		"short": {
+			args:     []string{"-o=YAML"},
+			expected: "yaml",
+		},
+		"alias": {
+			args:     []string{"-o", "yml"},
+			expected: "yml",
+		},
+		"invalid": {
+			args: []string{"-o", "xml"},
+			err:  `invalid value "xml" for flag -o: invalid encoding "xml" (valid values are table, detail, json, yaml, csv or raw)`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(ioutil.Discard)
+			encoding := OutputFlag(fs)
+
+			err := fs.Parse(c.args)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, *encoding)
+		})
+	}
+}
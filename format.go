@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// now returns the current time. This is a variable so we can mock it in tests.
+var now = time.Now
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// formatTime formats t according to the "format" and "since" options of the
+// table tag. By default times are formatted as RFC 3339.
+func formatTime(t time.Time, tag tableTag) string {
+	switch {
+	case tag.Since:
+		return humanizeSince(t)
+	case tag.Format != "":
+		return t.Format(tag.Format)
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
+// humanizeSince returns the time that has passed since t in a short human
+// readable form such as "3h ago" or "in 5m" if t lies in the future.
+func humanizeSince(t time.Time) string {
+	d := now().Sub(t)
+	if d < 0 {
+		return "in " + humanizeDuration(-d)
+	}
+	return humanizeDuration(d) + " ago"
+}
+
+// humanizeDuration returns d in its largest unit (e.g. "42s", "5m", "3h" or
+// "2d").
+func humanizeDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return strconv.Itoa(int(d/time.Second)) + "s"
+	case d < time.Hour:
+		return strconv.Itoa(int(d/time.Minute)) + "m"
+	case d < 24*time.Hour:
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	default:
+		return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	}
+}
+
+// formatDuration rounds d to the given precision and returns it without any
+// trailing zero units (e.g. "1h12m" instead of "1h12m0s"). If precision is 0
+// it is chosen depending on the magnitude of d so that the result contains at
+// most two units.
+func formatDuration(d time.Duration, precision time.Duration) string {
+	if precision == 0 {
+		abs := d
+		if abs < 0 {
+			abs = -abs
+		}
+
+		switch {
+		case abs >= time.Hour:
+			precision = time.Minute
+		case abs >= time.Minute:
+			precision = time.Second
+		case abs >= time.Second:
+			precision = 10 * time.Millisecond
+		case abs >= time.Millisecond:
+			precision = time.Millisecond
+		default:
+			precision = 1
+		}
+	}
+
+	s := d.Round(precision).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// formatBytes formats the numeric value v as a byte size using binary units
+// (e.g. "340 MiB" or "1.2 GiB"). Values below 100 units are printed with a
+// single decimal place.
+func formatBytes(v reflect.Value) string {
+	n := toFloat(v)
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+
+	unit := 0
+	for n >= 1024 && unit < len(byteUnits)-1 {
+		n /= 1024
+		unit++
+	}
+
+	s := fmt.Sprintf("%.0f", n)
+	if unit > 0 && n < 100 {
+		s = strings.TrimSuffix(fmt.Sprintf("%.1f", n), ".0")
+	}
+
+	return sign + s + " " + byteUnits[unit]
+}
+
+// formatNumber formats the numeric value v according to the "decimals" tag
+// option and the NumberFormat option. Without any of these options numbers are
+// formatted via fmt.Sprint.
+func formatNumber(v reflect.Value, tag tableTag, opts *options) string {
+	if !tag.HasDecimals && opts.thousandsSeparator == "" && opts.decimalSeparator == "." {
+		switch {
+		case isInt(v.Kind()):
+			return strconv.FormatInt(v.Int(), 10)
+		case isUint(v.Kind()):
+			return strconv.FormatUint(v.Uint(), 10)
+		default:
+			return fmt.Sprint(v.Interface())
+		}
+	}
+
+	var s string
+	switch {
+	case isInt(v.Kind()):
+		s = strconv.FormatInt(v.Int(), 10)
+	case isUint(v.Kind()):
+		s = strconv.FormatUint(v.Uint(), 10)
+	case tag.HasDecimals:
+		s = strconv.FormatFloat(v.Float(), 'f', tag.Decimals, v.Type().Bits())
+	default:
+		s = strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	}
+
+	return groupDigits(s, opts.thousandsSeparator, opts.decimalSeparator)
+}
+
+// groupDigits inserts the thousands separator between each group of three
+// digits of the integer part of the decimal number s and replaces its decimal
+// point with the given decimal separator.
+func groupDigits(s, thousands, decimal string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	integer, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer, fraction = s[:i], decimal+s[i+1:]
+	}
+
+	// Values such as NaN and Inf are not grouped.
+	if strings.Trim(integer, "0123456789") != "" {
+		return sign + s
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(c)
+	}
+	b.WriteString(fraction)
+	return b.String()
+}
+
+// formatBool formats b according to the "bool" tag option and the Booleans
+// option.
+func formatBool(b bool, tag tableTag, opts *options) string {
+	trueText, falseText := opts.trueText, opts.falseText
+	if tag.True != "" {
+		trueText, falseText = tag.True, tag.False
+	}
+
+	if b {
+		return trueText
+	}
+	return falseText
+}
+
+// A HeaderCase controls how the names of struct fields are converted to column
+// names by the "table" and "detail" encodings. Column names that are set via
+// the "table" tag are never converted.
+type HeaderCase int
+
+// The header cases that can be set via the HeaderCasing option.
+const (
+	HeaderUpperCase HeaderCase = iota // e.g. "CREATEDAT" (default)
+	HeaderFieldName                   // e.g. "CreatedAt"
+	HeaderTitleCase                   // e.g. "Created At"
+	HeaderSnakeCase                   // e.g. "created_at"
+)
+
+// apply converts the struct field name to a column name.
+func (c HeaderCase) apply(name string) string {
+	switch c {
+	case HeaderFieldName:
+		return name
+	case HeaderTitleCase:
+		return strings.Join(splitWords(name), " ")
+	case HeaderSnakeCase:
+		return strings.ToLower(strings.Join(splitWords(name), "_"))
+	default:
+		return strings.ToUpper(name)
+	}
+}
+
+// splitWords splits a camel case identifier into its words. Acronyms are kept
+// together (e.g. "HTTPStatusCode" is split into "HTTP", "Status" and "Code").
+// Underscores are treated as word boundaries.
+func splitWords(name string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	r := []rune(name)
+	for i, c := range r {
+		if c == '_' {
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = nil
+			continue
+		}
+
+		if len(word) > 0 && unicode.IsUpper(c) {
+			prev := r[i-1]
+			nextLower := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, c)
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanizeSince(t *testing.T) {
+	defer func() { now = time.Now }()
+	ref := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time { return ref }
+
+	cases := map[string]struct {
+		t        time.Time
+		expected string
+	}{
+		"seconds": {ref.Add(-42 * time.Second), "42s ago"},
+		"minutes": {ref.Add(-5*time.Minute - 10*time.Second), "5m ago"},
+		"hours":   {ref.Add(-3 * time.Hour), "3h ago"},
+		"days":    {ref.Add(-50 * time.Hour), "2d ago"},
+		"future":  {ref.Add(90 * time.Minute), "in 1h"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, humanizeSince(c.t))
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := map[string]struct {
+		d         time.Duration
+		precision time.Duration
+		expected  string
+	}{
+		"zero":         {0, 0, "0s"},
+		"nanoseconds":  {42, 0, "42ns"},
+		"milliseconds": {1500 * time.Microsecond, 0, "2ms"},
+		"seconds":      {1234 * time.Millisecond, 0, "1.23s"},
+		"minutes":      {3*time.Minute + 5500*time.Millisecond, 0, "3m6s"},
+		"full minutes": {3 * time.Minute, 0, "3m"},
+		"hours":        {72 * time.Minute, 0, "1h12m"},
+		"full hours":   {2*time.Hour + 10*time.Second, 0, "2h"},
+		"negative":     {-72 * time.Minute, 0, "-1h12m"},
+		"precision":    {72*time.Minute + 5*time.Second, time.Second, "1h12m5s"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, formatDuration(c.d, c.precision))
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[string]struct {
+		value    interface{}
+		expected string
+	}{
+		"zero":      {0, "0 B"},
+		"bytes":     {1023, "1023 B"},
+		"kibibytes": {1024, "1 KiB"},
+		"decimal":   {int64(1288490189), "1.2 GiB"},
+		"large":     {uint64(340 << 20), "340 MiB"},
+		"negative":  {-1536, "-1.5 KiB"},
+		"float":     {float64(2 << 40), "2 TiB"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, formatBytes(reflect.ValueOf(c.value)))
+		})
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	cases := map[string]struct {
+		value    interface{}
+		tag      tableTag
+		opts     []Option
+		expected string
+	}{
+		"default int":      {1234567, tableTag{}, nil, "1234567"},
+		"default float":    {1.5, tableTag{}, nil, "1.5"},
+		"grouped int":      {1234567, tableTag{}, []Option{NumberFormat(",", ".")}, "1,234,567"},
+		"grouped uint":     {uint64(123456), tableTag{}, []Option{NumberFormat(",", ".")}, "123,456"},
+		"grouped small":    {123, tableTag{}, []Option{NumberFormat(",", ".")}, "123"},
+		"grouped negative": {-1234, tableTag{}, []Option{NumberFormat(",", ".")}, "-1,234"},
+		"grouped float":    {1234567.25, tableTag{}, []Option{NumberFormat(".", ",")}, "1.234.567,25"},
+		"decimals":         {3.14159, tableTag{Decimals: 2, HasDecimals: true}, nil, "3.14"},
+		"zero decimals":    {2.5e9, tableTag{HasDecimals: true}, []Option{NumberFormat(",", ".")}, "2,500,000,000"},
+		"nan":              {math.NaN(), tableTag{}, []Option{NumberFormat(",", ".")}, "NaN"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, formatNumber(reflect.ValueOf(c.value), c.tag, newOptions(c.opts)))
+		})
+	}
+}
+
+func TestHeaderCase(t *testing.T) {
+	cases := map[string]struct {
+		name                           string
+		upper, fieldName, title, snake string
+	}{
+		"single word": {"Name", "NAME", "Name", "Name", "name"},
+		"camel case":  {"CreatedAt", "CREATEDAT", "CreatedAt", "Created At", "created_at"},
+		"acronym":     {"HTTPStatusCode", "HTTPSTATUSCODE", "HTTPStatusCode", "HTTP Status Code", "http_status_code"},
+		"suffix":      {"UserID", "USERID", "UserID", "User ID", "user_id"},
+		"digits":      {"Field2Value", "FIELD2VALUE", "Field2Value", "Field2 Value", "field2_value"},
+		"underscores": {"Max_Size", "MAX_SIZE", "Max_Size", "Max Size", "max_size"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.upper, HeaderUpperCase.apply(c.name))
+			assert.Equal(t, c.fieldName, HeaderFieldName.apply(c.name))
+			assert.Equal(t, c.title, HeaderTitleCase.apply(c.name))
+			assert.Equal(t, c.snake, HeaderSnakeCase.apply(c.name))
+		})
+	}
+}
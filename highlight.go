@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// The styles of the syntax highlighting of the "json" and "yaml" encodings.
+const (
+	syntaxKeyStyle    = Blue + ";" + Bold
+	syntaxStringStyle = Green
+	syntaxNumberStyle = Cyan
+	syntaxBoolStyle   = Yellow
+	syntaxNullStyle   = Gray
+)
+
+// highlightJSON returns a copy of the JSON document b in which keys, strings,
+// numbers and literals are styled. The whitespace of the document is kept.
+func highlightJSON(b []byte) []byte {
+	out := make([]byte, 0, len(b)*2)
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c == '"':
+			end := i + 1
+			for end < len(b) && b[end] != '"' {
+				if b[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(b) {
+				end++
+			}
+
+			style := syntaxStringStyle
+			if isJSONKey(b[end:]) {
+				style = syntaxKeyStyle
+			}
+			out = append(out, style.apply(string(b[i:end]))...)
+			i = end
+		case c == '-' || c >= '0' && c <= '9':
+			end := i + 1
+			for end < len(b) && strings.IndexByte("0123456789.eE+-", b[end]) >= 0 {
+				end++
+			}
+			out = append(out, syntaxNumberStyle.apply(string(b[i:end]))...)
+			i = end
+		case bytes.HasPrefix(b[i:], []byte("true")):
+			out = append(out, syntaxBoolStyle.apply("true")...)
+			i += 4
+		case bytes.HasPrefix(b[i:], []byte("false")):
+			out = append(out, syntaxBoolStyle.apply("false")...)
+			i += 5
+		case bytes.HasPrefix(b[i:], []byte("null")):
+			out = append(out, syntaxNullStyle.apply("null")...)
+			i += 4
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+	return out
+}
+
+// isJSONKey returns true if the JSON string that was followed by b is an
+// object key.
+func isJSONKey(b []byte) bool {
+	b = bytes.TrimLeft(b, " \t\r\n")
+	return len(b) > 0 && b[0] == ':'
+}
+
+var (
+	// yamlLine matches a line of YAML and captures its indentation, any list
+	// indicators, the key and the value. The key is empty if the line does
+	// not contain a mapping.
+	yamlLine = regexp.MustCompile(`^(\s*)((?:- )*)(?:((?:"(?:[^"\\]|\\.)*"|'[^']*'|[^\s'"#][^:#]*?)):(?: |$))?(.*)$`)
+
+	yamlNumber = regexp.MustCompile(`^[-+]?(\.inf|\.Inf|\.nan|\.NaN|\d[\d_]*(\.\d*)?([eE][-+]?\d+)?|0x[0-9a-fA-F]+|0o[0-7]+)$`)
+)
+
+// highlightYAML returns a copy of the YAML document b in which keys and scalar
+// values are styled. It only supports documents as they are produced by
+// yaml.Marshal.
+func highlightYAML(b []byte) []byte {
+	lines := strings.Split(string(b), "\n")
+
+	// block is the indentation of the key of a block scalar (e.g. "key: |")
+	// whose content lines are currently highlighted or -1.
+	block := -1
+	for n, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if block >= 0 {
+			if strings.TrimSpace(line) == "" || indent > block {
+				lines[n] = line[:indent] + syntaxStringStyle.apply(line[indent:])
+				continue
+			}
+			block = -1
+		}
+
+		m := yamlLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		prefix, key, value := m[1]+m[2], m[3], m[4]
+		if key != "" {
+			prefix += syntaxKeyStyle.apply(key) + ":"
+			if value != "" {
+				prefix += " "
+			}
+		}
+
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			block = len(m[1]) + len(m[2])
+			lines[n] = prefix + value
+			continue
+		}
+
+		lines[n] = prefix + highlightYAMLScalar(value)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// highlightYAMLScalar returns the styled YAML scalar s. Flow collections (e.g.
+// "[]" and "{}") are not styled.
+func highlightYAMLScalar(s string) string {
+	switch {
+	case s == "" || s == "[]" || s == "{}":
+		return s
+	case s == "null" || s == "~":
+		return syntaxNullStyle.apply(s)
+	case s == "true" || s == "false":
+		return syntaxBoolStyle.apply(s)
+	case yamlNumber.MatchString(s):
+		return syntaxNumberStyle.apply(s)
+	default:
+		return syntaxStringStyle.apply(s)
+	}
+}
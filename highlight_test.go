@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlightJSON(t *testing.T) {
+	in := `{"name": "a \"b\"", "n": -1.5e3, "ok": true, "no": false, "x": null, "list": ["c"]}`
+	expected := `{` +
+		"\x1b[34;1m\"name\"\x1b[0m: \x1b[32m\"a \\\"b\\\"\"\x1b[0m, " +
+		"\x1b[34;1m\"n\"\x1b[0m: \x1b[36m-1.5e3\x1b[0m, " +
+		"\x1b[34;1m\"ok\"\x1b[0m: \x1b[33mtrue\x1b[0m, " +
+		"\x1b[34;1m\"no\"\x1b[0m: \x1b[33mfalse\x1b[0m, " +
+		"\x1b[34;1m\"x\"\x1b[0m: \x1b[90mnull\x1b[0m, " +
+		"\x1b[34;1m\"list\"\x1b[0m: [\x1b[32m\"c\"\x1b[0m]}"
+	assert.Equal(t, expected, string(highlightJSON([]byte(in))))
+}
+
+func TestHighlightYAML(t *testing.T) {
+	in := "name: alice\n" +
+		"age: 42\n" +
+		"admin: false\n" +
+		"boss: null\n" +
+		"tags:\n" +
+		"- a\n" +
+		"- x: 1\n" +
+		"  \"y:z\": \"2\"\n" +
+		"bio: |-\n" +
+		"  key: not a key\n" +
+		"\n" +
+		"  more\n" +
+		"empty: []\n"
+
+	expected := "\x1b[34;1mname\x1b[0m: \x1b[32malice\x1b[0m\n" +
+		"\x1b[34;1mage\x1b[0m: \x1b[36m42\x1b[0m\n" +
+		"\x1b[34;1madmin\x1b[0m: \x1b[33mfalse\x1b[0m\n" +
+		"\x1b[34;1mboss\x1b[0m: \x1b[90mnull\x1b[0m\n" +
+		"\x1b[34;1mtags\x1b[0m:\n" +
+		"- \x1b[32ma\x1b[0m\n" +
+		"- \x1b[34;1mx\x1b[0m: \x1b[36m1\x1b[0m\n" +
+		"  \x1b[34;1m\"y:z\"\x1b[0m: \x1b[32m\"2\"\x1b[0m\n" +
+		"\x1b[34;1mbio\x1b[0m: |-\n" +
+		"  \x1b[32mkey: not a key\x1b[0m\n" +
+		"\n" +
+		"  \x1b[32mmore\x1b[0m\n" +
+		"\x1b[34;1mempty\x1b[0m: []\n"
+
+	assert.Equal(t, expected, string(highlightYAML([]byte(in))))
+}
+
+func TestPrintWriter_Highlight(t *testing.T) {
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("json", []int{1}, out, Color(ColorAlways)))
+	assert.Equal(t, "[\n    \x1b[36m1\x1b[0m\n]\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("yaml", map[string]int{"a": 1}, out, Color(ColorAlways)))
+	assert.Equal(t, "\x1b[34;1ma\x1b[0m: \x1b[36m1\x1b[0m\n\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("json", map[string]int{"a": 1}, out))
+	assert.Equal(t, "{\n    \"a\": 1\n}\n", out.String())
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v2"
+)
+
+// ErrNoInput is returned by ReadJSON and ReadYAML if stdin is a terminal.
+// In this case the user most likely forgot to pipe the input to the command.
+var ErrNoInput = errors.New("no input: stdin is a terminal but the input must be piped to the command")
+
+// stdinIsTerminal returns true if stdin refers to a terminal.
+func stdinIsTerminal() bool {
+	f, ok := stdin.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// makeStdinRaw puts stdin into raw mode and returns a function that restores
+// its previous state. It returns false if stdin is not a terminal.
+func makeStdinRaw() (restore func(), ok bool, err error) {
+	if !stdinIsTerminal() {
+		return nil, false, nil
+	}
+
+	fd := int(stdin.(*os.File).Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, false, err
+	}
+	return func() { term.Restore(fd, state) }, true, nil
+}
+
+// readInput reads the whole document that was piped to stdin. It returns
+// ErrNoInput if stdin is a terminal.
+func readInput(ctx context.Context) ([]byte, error) {
+	if stdinIsTerminal() {
+		return nil, ErrNoInput
+	}
+	return ReadAll(ctx)
+}
+
+// ReadJSON reads all of stdin like ReadAll does and stores the decoded JSON in
+// the value pointed to by v. It returns ErrNoInput if stdin is a terminal
+// instead of waiting for the user to type the document.
+func ReadJSON(ctx context.Context, v interface{}) error {
+	data, err := readInput(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid JSON input: %w", err)
+	}
+	return nil
+}
+
+// ReadYAML reads all of stdin like ReadJSON does and stores the decoded YAML in
+// the value pointed to by v. If the input is a stream of multiple documents,
+// only the first document is decoded. Use ReadYAMLDocuments to decode all of
+// them.
+func ReadYAML(ctx context.Context, v interface{}) error {
+	data, err := readInput(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid YAML input: %w", err)
+	}
+	return nil
+}
+
+// ReadYAMLDocuments reads a stream of YAML documents from stdin like ReadYAML
+// does and stores them in the slice that v points to. Each document is decoded
+// into a new element of the slice:
+//
+//	var items []Item
+//	err := cli.ReadYAMLDocuments(ctx, &items)
+func ReadYAMLDocuments(ctx context.Context, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("cannot decode YAML documents into %T: value must be a pointer to a slice", v)
+	}
+
+	data, err := readInput(ctx)
+	if err != nil {
+		return err
+	}
+
+	docs := rv.Elem()
+	docs.SetLen(0)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		doc := reflect.New(docs.Type().Elem())
+		err := dec.Decode(doc.Interface())
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid YAML input: %w", err)
+		}
+		docs.Set(reflect.Append(docs, doc.Elem()))
+	}
+}
+
+// ReadNDJSON reads newline delimited JSON from stdin and decodes each line
+// into a value of type T. Blank lines are ignored. Values and errors are
+// reported like ReadLinesAs does, so reading stops at the first line that is
+// not valid JSON.
+func ReadNDJSON[T any](ctx context.Context) (<-chan T, <-chan error) {
+	return readLinesAs(ctx, func(line string) (T, error) {
+		var v T
+		err := json.Unmarshal([]byte(line), &v)
+		return v, err
+	}, true)
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadJSON(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader(`{"name": "foo", "tags": ["a", "b"]}`)
+
+	var v struct {
+		Name string
+		Tags []string
+	}
+	assert.NoError(t, ReadJSON(context.Background(), &v))
+	assert.Equal(t, "foo", v.Name)
+	assert.Equal(t, []string{"a", "b"}, v.Tags)
+}
+
+func TestReadJSON_Invalid(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader(`{"name": `)
+
+	var v interface{}
+	err := ReadJSON(context.Background(), &v)
+	assert.EqualError(t, err, "invalid JSON input: unexpected end of JSON input")
+}
+
+func TestReadYAML(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("name: foo\ntags: [a, b]\n")
+
+	var v struct {
+		Name string
+		Tags []string
+	}
+	assert.NoError(t, ReadYAML(context.Background(), &v))
+	assert.Equal(t, "foo", v.Name)
+	assert.Equal(t, []string{"a", "b"}, v.Tags)
+}
+
+func TestReadYAMLDocuments(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("name: foo\n---\nname: bar\n")
+
+	type item struct{ Name string }
+	items := []item{{Name: "existing"}}
+	assert.NoError(t, ReadYAMLDocuments(context.Background(), &items))
+	assert.Equal(t, []item{{Name: "foo"}, {Name: "bar"}}, items)
+
+	var v item
+	err := ReadYAMLDocuments(context.Background(), &v)
+	assert.EqualError(t, err, "cannot decode YAML documents into *cli.item: value must be a pointer to a slice")
+}
+
+func TestReadNDJSON(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("{\"name\": \"foo\"}\n\n{\"name\": \"bar\"}\r\n{\"name\":\n")
+
+	type item struct{ Name string }
+	values, errs := ReadNDJSON[item](context.Background())
+
+	var items []item
+	for v := range values {
+		items = append(items, v)
+	}
+
+	assert.Equal(t, []item{{Name: "foo"}, {Name: "bar"}}, items)
+	assert.EqualError(t, <-errs, "line 4: unexpected end of JSON input")
+}
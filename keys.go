@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"unicode/utf8"
+)
+
+// A key is a key that was pressed on a terminal in raw mode.
+type key int
+
+// The keys that are recognized by readKeyPresses. Printable characters are
+// reported as keyRune.
+const (
+	keyRune key = iota
+	keyEnter
+	keyTab
+	keyBackspace
+	keyDelete
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyInterrupt // Ctrl+C
+	keyEOF       // Ctrl+D
+	keyClear     // Ctrl+U
+)
+
+// A keyPress is a single key press. R is the typed character if Key is
+// keyRune.
+type keyPress struct {
+	Key key
+	R   rune
+}
+
+// escapeKeys maps the final bytes of ANSI escape sequences (e.g. "\x1b[A") to
+// the keys they represent.
+var escapeKeys = map[string]key{
+	"A":  keyUp,
+	"B":  keyDown,
+	"C":  keyRight,
+	"D":  keyLeft,
+	"H":  keyHome,
+	"F":  keyEnd,
+	"1~": keyHome,
+	"7~": keyHome,
+	"4~": keyEnd,
+	"8~": keyEnd,
+	"3~": keyDelete,
+}
+
+// readKeyPresses reads from a terminal in raw mode like lineReader.readKeys
+// does but decodes escape sequences and UTF-8 encoded characters, so fn is
+// called once per key press. Unknown escape sequences and control characters
+// are ignored.
+func readKeyPresses(ctx context.Context, lr *lineReader, fn func(keyPress) (bool, error)) error {
+	var buf []byte
+	return lr.readKeys(ctx, func(b byte) (bool, error) {
+		if len(buf) > 0 && buf[0] == 0x1b {
+			buf = append(buf, b)
+			switch {
+			case len(buf) == 2:
+				if b != '[' && b != 'O' {
+					buf = buf[:0] // Alt+key
+				}
+				return false, nil
+			case b < 0x40 || b > 0x7e:
+				// A parameter byte of the sequence.
+				if len(buf) > 8 {
+					buf = buf[:0]
+				}
+				return false, nil
+			}
+
+			k, ok := escapeKeys[string(buf[2:])]
+			buf = buf[:0]
+			if !ok {
+				return false, nil
+			}
+			return fn(keyPress{Key: k})
+		}
+
+		if len(buf) > 0 || b >= utf8.RuneSelf {
+			buf = append(buf, b)
+			if !utf8.FullRune(buf) {
+				return false, nil
+			}
+			r, _ := utf8.DecodeRune(buf)
+			buf = buf[:0]
+			return fn(keyPress{Key: keyRune, R: r})
+		}
+
+		switch b {
+		case 0x1b:
+			buf = append(buf, b)
+			return false, nil
+		case '\r', '\n':
+			return fn(keyPress{Key: keyEnter})
+		case '\t':
+			return fn(keyPress{Key: keyTab})
+		case 8, 127:
+			return fn(keyPress{Key: keyBackspace})
+		case 3:
+			return fn(keyPress{Key: keyInterrupt})
+		case 4:
+			return fn(keyPress{Key: keyEOF})
+		case 21:
+			return fn(keyPress{Key: keyClear})
+		}
+
+		if b < ' ' {
+			return false, nil
+		}
+		return fn(keyPress{Key: keyRune, R: rune(b)})
+	})
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadKeyPresses(t *testing.T) {
+	lr := newLineReader(strings.NewReader("a\x1b[A\x1bOBä\x1b[3~\x1b[5~\x1bx\x7f\x01\r"))
+
+	var keys []keyPress
+	err := readKeyPresses(context.Background(), lr, func(k keyPress) (bool, error) {
+		keys = append(keys, k)
+		return k.Key == keyEnter, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []keyPress{
+		{Key: keyRune, R: 'a'},
+		{Key: keyUp},
+		{Key: keyDown},
+		{Key: keyRune, R: 'ä'},
+		{Key: keyDelete},
+		{Key: keyBackspace},
+		{Key: keyEnter},
+	}, keys)
+}
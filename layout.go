@@ -0,0 +1,643 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Layout parameters of the "table" encoding. This matches the output of a
+// text/tabwriter.Writer with a minimum width of 8 and a padding of 2.
+const (
+	minCellWidth = 8
+	cellPadding  = 2
+)
+
+// minTruncateWidth is the minimum width to which cells are truncated if a
+// table does not fit into its maximum width.
+const minTruncateWidth = minCellWidth - cellPadding
+
+// tableLayout contains the formatted cells of a table and renders them as
+// aligned columns.
+type tableLayout struct {
+	header  []string
+	records [][]string
+
+	// parents contains the name of the parent column of each column or the
+	// empty string if the column has no parent. Consecutive columns with
+	// the same parent are printed below a spanning header.
+	parents []string
+
+	// wraps contains the maximum width of each column or 0 if the column
+	// should not be wrapped.
+	wraps []int
+
+	// minWidths contains the minimum width of the content of each column.
+	minWidths []int
+
+	headerStyle Style
+
+	// footer is true if the last record is the footer of the table.
+	footer bool
+
+	// sections contains the titles of sections by the index of the first
+	// record of the section. Section titles are printed on their own line
+	// and do not affect the width of the columns.
+	sections map[int]string
+
+	// border is used to draw borders around the cells. If it is the zero
+	// value the table is printed without borders.
+	border BorderStyle
+
+	// styles contains the style of each cell of each record. It may be
+	// shorter than records or nil if records should not be styled.
+	styles [][]Style
+
+	// widths contains the minimum width of each column. After the table has
+	// been written it contains the actual widths of the columns. This is
+	// used to keep the columns of a stream aligned across multiple writes.
+	widths []int
+
+	// skipHeader is true if the header should not be printed (e.g. because
+	// it has already been printed as part of a previous write).
+	skipHeader bool
+
+	// maxWidth is the maximum width of the table. If the table is wider, the
+	// cells of the widest columns are truncated. If it is 0 the width of the
+	// table is not limited.
+	maxWidth int
+
+	// limits contains the maximum width of the cells of each column or 0 if
+	// the cells should not be truncated. It is set by shrink.
+	limits []int
+}
+
+// tableLine is a single physical line of a table.
+type tableLine struct {
+	cells  []string
+	styles []Style
+
+	// header is true if this line is the header of the table. The last cell
+	// of the header line is not padded and does not contribute to the width
+	// of the last column.
+	header bool
+
+	// footer is true if this line belongs to the footer of the table.
+	footer bool
+
+	// section is true if this line is a section title. Its only cell spans
+	// all columns of the table.
+	section bool
+}
+
+func (l *tableLayout) write(w io.Writer) error {
+	l.shrink()
+	if l.border != (BorderStyle{}) {
+		return l.writeBordered(w)
+	}
+
+	lines := l.lines()
+	if l.skipHeader {
+		lines = lines[1:]
+	}
+
+	widths := l.columnWidths(lines)
+	groups := l.parentGroups()
+	l.widths = widths
+
+	buf := new(bytes.Buffer)
+	if len(groups) > 0 && !l.skipHeader {
+		for i := 0; i < len(widths); {
+			g, ok := groupAt(groups, i)
+			if !ok {
+				buf.WriteString(strings.Repeat(" ", widths[i]))
+				i++
+				continue
+			}
+
+			buf.WriteString(l.headerStyle.apply(g.title))
+			buf.WriteString(strings.Repeat(" ", g.width(widths, 0)-textWidth(g.title)))
+			i = g.end
+		}
+
+		line := strings.TrimRight(buf.String(), " ") + "\n"
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+		buf.Reset()
+	}
+
+	for n, line := range lines {
+		buf.Reset()
+		if line.section {
+			// Sections are separated by an empty line.
+			if n > 0 && !lines[n-1].header {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(line.styles[0].apply(line.cells[0]) + "\n")
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for i, cell := range line.cells {
+			var style Style
+			if i < len(line.styles) {
+				style = line.styles[i]
+			}
+
+			buf.WriteString(style.apply(cell))
+			if i == len(line.cells)-1 {
+				continue
+			}
+			buf.WriteString(strings.Repeat(" ", widths[i]-textWidth(cell)))
+		}
+
+		// Lines never end with whitespace, even if their last cells are
+		// empty, so the output is stable for golden files and diffs.
+		out := append(bytes.TrimRight(buf.Bytes(), " "), '\n')
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeBordered renders the table with borders around each cell.
+func (l *tableLayout) writeBordered(w io.Writer) error {
+	lines := l.lines()
+	widths := l.columnWidths(lines)
+	groups := l.parentGroups()
+
+	// The inner width of the table is used to print section titles.
+	inner := 3*len(widths) - 3
+	for _, n := range widths {
+		inner += n
+	}
+
+	b := l.border
+	separator := func(left, mid, right string) string {
+		parts := make([]string, len(widths))
+		for i, n := range widths {
+			parts[i] = strings.Repeat(b.Horizontal, n+2)
+		}
+		return left + strings.Join(parts, mid) + right + "\n"
+	}
+
+	buf := new(bytes.Buffer)
+	if len(groups) == 0 {
+		buf.WriteString(separator(b.TopLeft, b.TopMid, b.TopRight))
+	} else {
+		// The top border of the spanning headers has no joints between
+		// the columns of each group.
+		var parts []string
+		for i := 0; i < len(widths); {
+			width, end := widths[i], i+1
+			if g, ok := groupAt(groups, i); ok {
+				width, end = g.width(widths, 3), g.end
+			}
+			parts = append(parts, strings.Repeat(b.Horizontal, width+2))
+			i = end
+		}
+		buf.WriteString(b.TopLeft + strings.Join(parts, b.TopMid) + b.TopRight + "\n")
+
+		buf.WriteString(b.Vertical)
+		for i := 0; i < len(widths); {
+			title, width, end := "", widths[i], i+1
+			if g, ok := groupAt(groups, i); ok {
+				title, width, end = g.title, g.width(widths, 3), g.end
+			}
+
+			buf.WriteString(" " + l.headerStyle.apply(title))
+			buf.WriteString(strings.Repeat(" ", width-textWidth(title)+1))
+			buf.WriteString(b.Vertical)
+			i = end
+		}
+		buf.WriteString("\n")
+		buf.WriteString(separator(b.MidLeft, b.Mid, b.MidRight))
+	}
+
+	for n, line := range lines {
+		if line.footer && !lines[n-1].footer {
+			buf.WriteString(separator(b.MidLeft, b.Mid, b.MidRight))
+		}
+
+		if line.section {
+			if !lines[n-1].header {
+				buf.WriteString(separator(b.MidLeft, b.Mid, b.MidRight))
+			}
+			cell := line.cells[0]
+			pad := inner - textWidth(cell)
+			if pad < 0 {
+				pad = 0
+			}
+			buf.WriteString(b.Vertical + " " + line.styles[0].apply(cell))
+			buf.WriteString(strings.Repeat(" ", pad+1) + b.Vertical + "\n")
+			buf.WriteString(separator(b.MidLeft, b.Mid, b.MidRight))
+			continue
+		}
+
+		buf.WriteString(b.Vertical)
+		for i, cell := range line.cells {
+			var style Style
+			if i < len(line.styles) {
+				style = line.styles[i]
+			}
+
+			buf.WriteString(" " + style.apply(cell))
+			buf.WriteString(strings.Repeat(" ", widths[i]-textWidth(cell)+1))
+			buf.WriteString(b.Vertical)
+		}
+		buf.WriteString("\n")
+
+		if line.header {
+			buf.WriteString(separator(b.MidLeft, b.Mid, b.MidRight))
+		}
+	}
+	buf.WriteString(separator(b.BottomLeft, b.BottomMid, b.BottomRight))
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// columnWidths returns the width of each column of the given lines. Without
+// borders the widths include the padding between the columns and are at least
+// as wide as the widths of a previous write.
+func (l *tableLayout) columnWidths(lines []tableLine) []int {
+	return l.fitWidths(l.contentWidths(lines))
+}
+
+// contentWidths returns the width of the widest cell of each column of the
+// given lines. Without borders the last cell of the header is ignored because
+// it is not padded.
+func (l *tableLayout) contentWidths(lines []tableLine) []int {
+	bordered := l.border != (BorderStyle{})
+	widths := make([]int, len(l.header))
+	for _, line := range lines {
+		for i, cell := range line.cells {
+			if line.section || !bordered && line.header && i == len(line.cells)-1 {
+				continue
+			}
+			if n := textWidth(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	return widths
+}
+
+// fitWidths returns the width of each column if the widest cells of the
+// columns have the given widths.
+func (l *tableLayout) fitWidths(content []int) []int {
+	bordered := l.border != (BorderStyle{})
+	widths := make([]int, len(l.header))
+	if !bordered {
+		copy(widths, l.widths)
+	}
+
+	for i, n := range content {
+		if !bordered {
+			n += cellPadding
+		}
+		if n > widths[i] {
+			widths[i] = n
+		}
+	}
+
+	for i, n := range l.minWidths {
+		if !bordered {
+			n += cellPadding
+		}
+		if n > widths[i] {
+			widths[i] = n
+		}
+	}
+
+	if bordered {
+		for _, g := range l.parentGroups() {
+			g.fit(widths, 3, 0)
+		}
+		return widths
+	}
+
+	for i := range widths {
+		if widths[i] < minCellWidth {
+			widths[i] = minCellWidth
+		}
+	}
+
+	for _, g := range l.parentGroups() {
+		g.fit(widths, 0, cellPadding)
+	}
+	return widths
+}
+
+// width returns the width of the widest line of the table.
+func (l *tableLayout) width() int {
+	lines := l.lines()
+	var header int
+	if last := len(l.header) - 1; last >= 0 {
+		header = textWidth(lines[0].cells[last])
+	}
+	return l.widthOf(l.contentWidths(lines), header)
+}
+
+// widthOf returns the width of the widest line of the table if the widest
+// cells of the columns have the given widths and the last cell of the header
+// has the given width.
+func (l *tableLayout) widthOf(content []int, header int) int {
+	widths := l.fitWidths(content)
+
+	var n int
+	for _, w := range widths {
+		n += w
+	}
+
+	if l.border != (BorderStyle{}) {
+		return n + 3*len(widths) + 1
+	}
+
+	// The last cell of the header is not padded but it may still be wider
+	// than the last column.
+	if last := len(widths) - 1; last >= 0 && !l.skipHeader {
+		if h := n - widths[last] + header; h > n {
+			return h
+		}
+	}
+	return n
+}
+
+// shrink truncates the cells of the widest columns until the table fits into
+// its maximum width or all columns have been truncated to minTruncateWidth.
+// The limits of the columns are computed from the widths of their cells, so
+// the cells are only truncated or wrapped once.
+func (l *tableLayout) shrink() {
+	if l.maxWidth <= 0 || len(l.header) == 0 {
+		return
+	}
+
+	l.limits = make([]int, len(l.header))
+	cells := l.contentWidths(l.lines())
+	last := len(cells) - 1
+	header := textWidth(l.header[last])
+
+	// limits contains the width of the widest cell of each column including
+	// the last cell of the header, which is truncated as well.
+	limits := append([]int(nil), cells...)
+	if header > limits[last] {
+		limits[last] = header
+	}
+
+	content := make([]int, len(cells))
+	for {
+		for i, n := range cells {
+			content[i] = n
+			if limits[i] < n {
+				content[i] = limits[i]
+			}
+		}
+		h := header
+		if limits[last] < h {
+			h = limits[last]
+		}
+
+		excess := l.widthOf(content, h) - l.maxWidth
+		if excess <= 0 {
+			return
+		}
+
+		widest := -1
+		for i, n := range limits {
+			if n > minTruncateWidth && (widest < 0 || n > limits[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+
+		// Cut the widest column down to the width of the next widest
+		// column or by the remaining excess width, whichever is less.
+		next := minTruncateWidth
+		for i, n := range limits {
+			if i != widest && n > next && n <= limits[widest] {
+				next = n
+			}
+		}
+		cut := limits[widest] - next
+		if cut > excess {
+			cut = excess
+		}
+		if cut < 1 {
+			cut = 1
+		}
+		limits[widest] -= cut
+		l.limits[widest] = limits[widest]
+	}
+}
+
+// lines returns the physical lines of the table. Each record may span multiple
+// physical lines if one of its columns is wrapped. Columns that have no more
+// content are printed as empty cells to keep the remaining columns aligned.
+func (l *tableLayout) lines() []tableLine {
+	header := tableLine{cells: l.header, header: true}
+	if l.limits != nil {
+		header.cells = make([]string, len(l.header))
+		for i, cell := range l.header {
+			header.cells[i] = truncate(cell, l.limits[i])
+		}
+	}
+
+	if l.headerStyle != "" {
+		header.styles = make([]Style, len(l.header))
+		for i := range header.styles {
+			header.styles[i] = l.headerStyle
+		}
+	}
+
+	var wrapped bool
+	for _, n := range l.wraps {
+		wrapped = wrapped || n > 0
+	}
+
+	lines := make([]tableLine, 1, len(l.records)+len(l.sections)+1)
+	lines[0] = header
+	for r, record := range l.records {
+		if title, ok := l.sections[r]; ok {
+			lines = append(lines, tableLine{
+				cells:   []string{title},
+				styles:  []Style{l.headerStyle},
+				section: true,
+			})
+		}
+
+		var styles []Style
+		if r < len(l.styles) {
+			styles = l.styles[r]
+		}
+
+		// Records without wrapped columns always fit onto a single line.
+		if !wrapped {
+			line := tableLine{
+				cells:  record,
+				styles: styles,
+				footer: l.footer && r == len(l.records)-1,
+			}
+			if l.limits != nil {
+				line.cells = make([]string, len(record))
+				for i, cell := range record {
+					line.cells[i] = truncate(cell, l.limits[i])
+				}
+			}
+			lines = append(lines, line)
+			continue
+		}
+
+		cells := make([][]string, len(record))
+		var height int
+		for i, cell := range record {
+			var limit int
+			if l.limits != nil {
+				limit = l.limits[i]
+			}
+
+			switch {
+			case l.wraps[i] > 0 && limit > 0 && limit < l.wraps[i]:
+				cells[i] = wrap(cell, limit)
+			case l.wraps[i] > 0:
+				cells[i] = wrap(cell, l.wraps[i])
+			default:
+				cells[i] = []string{truncate(cell, limit)}
+			}
+			if len(cells[i]) > height {
+				height = len(cells[i])
+			}
+		}
+
+		for n := 0; n < height; n++ {
+			line := tableLine{
+				cells:  make([]string, len(record)),
+				styles: styles,
+				footer: l.footer && r == len(l.records)-1,
+			}
+			for i := range cells {
+				if n < len(cells[i]) {
+					line.cells[i] = cells[i][n]
+				}
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// A parentGroup is a sequence of consecutive columns that share the same
+// parent column.
+type parentGroup struct {
+	title      string
+	start, end int
+}
+
+// parentGroups returns all groups of columns that have a parent.
+func (l *tableLayout) parentGroups() []parentGroup {
+	var groups []parentGroup
+	for i := 0; i < len(l.parents); i++ {
+		if l.parents[i] == "" {
+			continue
+		}
+
+		g := parentGroup{title: l.parents[i], start: i, end: i + 1}
+		for g.end < len(l.parents) && l.parents[g.end] == g.title {
+			g.end++
+		}
+		groups = append(groups, g)
+		i = g.end - 1
+	}
+	return groups
+}
+
+// groupAt returns the group that starts at column i.
+func groupAt(groups []parentGroup, i int) (parentGroup, bool) {
+	for _, g := range groups {
+		if g.start == i {
+			return g, true
+		}
+	}
+	return parentGroup{}, false
+}
+
+// width returns the total width of all columns of the group. The given gap is
+// the number of characters between two columns.
+func (g parentGroup) width(widths []int, gap int) int {
+	n := gap * (g.end - g.start - 1)
+	for _, w := range widths[g.start:g.end] {
+		n += w
+	}
+	return n
+}
+
+// fit increases the width of the last column of the group if the title of the
+// group does not fit into the columns.
+func (g parentGroup) fit(widths []int, gap, padding int) {
+	if d := textWidth(g.title) + padding - g.width(widths, gap); d > 0 {
+		widths[g.end-1] += d
+	}
+}
+
+// truncate shortens s to at most width characters. Truncated text ends with an
+// ellipsis and loses its ANSI escape sequences. If width is 0, s is returned
+// unchanged.
+func truncate(s string, width int) string {
+	if width <= 0 || textWidth(s) <= width {
+		return s
+	}
+	r := []rune(stripANSI(s))
+	return string(r[:width-1]) + "…"
+}
+
+// textWidth returns the number of characters of s as it would be displayed on
+// a terminal, ignoring any ANSI escape sequences.
+func textWidth(s string) int {
+	return utf8.RuneCountInString(stripANSI(s))
+}
+
+// A BorderStyle contains the strings that are used to draw borders around
+// table cells. Each string should have a width of a single character.
+type BorderStyle struct {
+	Horizontal string
+	Vertical   string
+
+	TopLeft  string
+	TopMid   string
+	TopRight string
+
+	MidLeft  string
+	Mid      string
+	MidRight string
+
+	BottomLeft  string
+	BottomMid   string
+	BottomRight string
+}
+
+var (
+	// BorderASCII draws table borders using ASCII characters.
+	BorderASCII = BorderStyle{
+		Horizontal: "-", Vertical: "|",
+		TopLeft: "+", TopMid: "+", TopRight: "+",
+		MidLeft: "+", Mid: "+", MidRight: "+",
+		BottomLeft: "+", BottomMid: "+", BottomRight: "+",
+	}
+
+	// BorderUnicode draws table borders using Unicode box drawing characters.
+	BorderUnicode = BorderStyle{
+		Horizontal: "─", Vertical: "│",
+		TopLeft: "┌", TopMid: "┬", TopRight: "┐",
+		MidLeft: "├", Mid: "┼", MidRight: "┤",
+		BottomLeft: "└", BottomMid: "┴", BottomRight: "┘",
+	}
+)
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// maxHistory is the maximum number of answers that are kept in the history of
+// the interactive prompts.
+const maxHistory = 1000
+
+// history contains the previous answers of all interactive prompts, the oldest
+// answer first.
+var history struct {
+	sync.Mutex
+	entries []string
+	file    string
+}
+
+// SetHistoryFile loads the history of the interactive prompts from the file
+// at the given path and appends all new answers to it, so they can be recalled
+// with the arrow keys after the application was restarted. The file does not
+// have to exist yet. Answers to ReadPassword and its variants are never added
+// to the history.
+func SetHistoryFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var entries []string
+	if f != nil {
+		defer f.Close()
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			if s.Text() != "" {
+				entries = append(entries, s.Text())
+			}
+		}
+		if err := s.Err(); err != nil {
+			return err
+		}
+	}
+
+	if len(entries) > maxHistory {
+		entries = entries[len(entries)-maxHistory:]
+	}
+
+	history.Lock()
+	defer history.Unlock()
+	history.entries = entries
+	history.file = path
+	return nil
+}
+
+// historyEntries returns a copy of the history.
+func historyEntries() []string {
+	history.Lock()
+	defer history.Unlock()
+	return append([]string(nil), history.entries...)
+}
+
+// addHistory adds an answer to the history unless it is empty or equal to the
+// previous answer. If a history file is set, the answer is appended to it.
+func addHistory(answer string) {
+	if strings.TrimSpace(answer) == "" {
+		return
+	}
+
+	history.Lock()
+	defer history.Unlock()
+	if n := len(history.entries); n > 0 && history.entries[n-1] == answer {
+		return
+	}
+
+	history.entries = append(history.entries, answer)
+	if len(history.entries) > maxHistory {
+		history.entries = history.entries[1:]
+	}
+
+	if history.file == "" {
+		return
+	}
+	f, err := os.OpenFile(history.file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return // the history is a convenience and must not break the prompt
+	}
+	defer f.Close()
+	fmt.Fprintln(f, answer)
+}
+
+// editLine reads a line from a terminal in raw mode and lets the user edit it
+// with the arrow keys, home, end, backspace and delete. The up and down arrow
+// keys recall the previous answers of the given history. The prompt and the
+// line are written to w and redrawn after each key press.
+func editLine(ctx context.Context, lr *lineReader, w io.Writer, prompt string, history []string) (string, error) {
+	var line []rune
+	pos := 0
+
+	// index is the position in the history. The current line is kept in
+	// draft while the user browses the history.
+	index := len(history)
+	var draft []rune
+
+	render := func() {
+		fmt.Fprint(w, "\r\x1b[K"+prompt+string(line))
+		if n := len(line) - pos; n > 0 {
+			fmt.Fprintf(w, "\x1b[%dD", n)
+		}
+	}
+
+	recall := func(i int) {
+		if index == len(history) {
+			draft = line
+		}
+		index = i
+		if index == len(history) {
+			line = draft
+		} else {
+			line = []rune(history[index])
+		}
+		pos = len(line)
+	}
+
+	render()
+	err := readKeyPresses(ctx, lr, func(k keyPress) (bool, error) {
+		switch k.Key {
+		case keyEnter:
+			return true, nil
+		case keyInterrupt:
+			ReceiveSignal(os.Interrupt)
+			return false, ErrInterrupted
+		case keyEOF:
+			if len(line) == 0 {
+				return false, io.EOF
+			}
+		case keyRune:
+			line = append(line[:pos], append([]rune{k.R}, line[pos:]...)...)
+			pos++
+		case keyBackspace:
+			if pos > 0 {
+				line = append(line[:pos-1], line[pos:]...)
+				pos--
+			}
+		case keyDelete:
+			if pos < len(line) {
+				line = append(line[:pos], line[pos+1:]...)
+			}
+		case keyClear:
+			line = line[pos:]
+			pos = 0
+		case keyLeft:
+			if pos > 0 {
+				pos--
+			}
+		case keyRight:
+			if pos < len(line) {
+				pos++
+			}
+		case keyHome:
+			pos = 0
+		case keyEnd:
+			pos = len(line)
+		case keyUp:
+			if index > 0 {
+				recall(index - 1)
+			}
+		case keyDown:
+			if index < len(history) {
+				recall(index + 1)
+			}
+		default:
+			return false, nil
+		}
+
+		render()
+		return false, nil
+	})
+
+	fmt.Fprint(w, "\r\n")
+	if err != nil {
+		return "", err
+	}
+	return string(line), nil
+}
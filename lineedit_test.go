@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditLine(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"typing":       {input: "hello\r", want: "hello"},
+		"backspace":    {input: "helo\x7f\x7fllo\r", want: "hello"},
+		"left right":   {input: "hllo\x1b[D\x1b[D\x1b[De\x1b[C\x1b[C\x1b[C!\r", want: "hello!"},
+		"home end":     {input: "ello\x1b[Hh\x1b[F!\r", want: "hello!"},
+		"delete":       {input: "hxello\x1b[H\x1b[C\x1b[3~\r", want: "hello"},
+		"clear":        {input: "foo bar\x1b[D\x1b[D\x1b[D\x15baz \r", want: "baz bar"},
+		"unicode":      {input: "grüße\x7f\r", want: "grüß"},
+		"history up":   {input: "\x1b[A\x1b[A\r", want: "first"},
+		"history down": {input: "draft\x1b[A\x1b[A\x1b[B\x1b[B\r", want: "draft"},
+		"history edit": {input: "\x1b[A!\r", want: "second!"},
+		"history top":  {input: "\x1b[A\x1b[A\x1b[A\r", want: "first"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			lr := newLineReader(strings.NewReader(tt.input))
+			line, err := editLine(context.Background(), lr, io.Discard, "> ", []string{"first", "second"})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, line)
+		})
+	}
+}
+
+func TestEditLineOutput(t *testing.T) {
+	lr := newLineReader(strings.NewReader("ac\x1b[Db\r"))
+	out := new(bytes.Buffer)
+
+	line, err := editLine(context.Background(), lr, out, "> ", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", line)
+	assert.Equal(t, "\r\x1b[K> \r\x1b[K> a\r\x1b[K> ac\r\x1b[K> ac\x1b[1D\r\x1b[K> abc\x1b[1D\r\n", out.String())
+}
+
+func TestEditLineEOF(t *testing.T) {
+	lr := newLineReader(strings.NewReader("\x04"))
+	_, err := editLine(context.Background(), lr, io.Discard, "> ", nil)
+	assert.Equal(t, io.EOF, err)
+
+	// Ctrl+D is ignored if the line is not empty.
+	lr = newLineReader(strings.NewReader("a\x04\r"))
+	line, err := editLine(context.Background(), lr, io.Discard, "> ", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", line)
+}
+
+func TestEditLineCanceled(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := editLine(ctx, newLineReader(r), io.Discard, "> ", nil)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestHistory(t *testing.T) {
+	defer resetHistory()
+	path := filepath.Join(t.TempDir(), "history")
+	require.NoError(t, os.WriteFile(path, []byte("one\n\ntwo\n"), 0600))
+
+	require.NoError(t, SetHistoryFile(path))
+	assert.Equal(t, []string{"one", "two"}, historyEntries())
+
+	addHistory("two")
+	addHistory("  ")
+	addHistory("three")
+	assert.Equal(t, []string{"one", "two", "three"}, historyEntries())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "one\n\ntwo\nthree\n", string(data))
+
+	// A history file that does not exist yet is created with the first answer.
+	path = filepath.Join(t.TempDir(), "new")
+	require.NoError(t, SetHistoryFile(path))
+	assert.Empty(t, historyEntries())
+
+	addHistory("four")
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "four\n", string(data))
+}
+
+func TestHistoryLimit(t *testing.T) {
+	defer resetHistory()
+	resetHistory()
+
+	for i := 0; i < maxHistory+5; i++ {
+		addHistory(strings.Repeat("x", i+1))
+	}
+
+	entries := historyEntries()
+	assert.Len(t, entries, maxHistory)
+	assert.Equal(t, strings.Repeat("x", 6), entries[0])
+}
+
+func TestAskAddsHistory(t *testing.T) {
+	defer resetHistory()
+	resetHistory()
+	mockPrompt(t, "us-east-1\n")
+
+	assert.Equal(t, "us-east-1", Ask(context.Background(), "Region: "))
+	assert.Equal(t, []string{"us-east-1"}, historyEntries())
+}
+
+func resetHistory() {
+	history.Lock()
+	defer history.Unlock()
+	history.entries = nil
+	history.file = ""
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageColor controls whether the symbols of status messages that are
+// printed via Successf, Warnf, Infof and Errorf are styled. By default they
+// are only styled if the standard error is a terminal and the NO_COLOR
+// environment variable is not set.
+var MessageColor = ColorAuto
+
+// The prefixes of the status messages.
+var (
+	successPrefix = Green.apply("✓")
+	warnPrefix    = Yellow.apply("!")
+	infoPrefix    = Blue.apply("i")
+	errorPrefix   = Red.apply("✗")
+)
+
+// Successf prints a message to the standard error which reports that an
+// operation has succeeded (e.g. "✓ created user alice"). Arguments are
+// handled in the manner of fmt.Printf and a newline is appended if the
+// message does not end with one.
+func Successf(format string, a ...interface{}) {
+	printMessage(successPrefix, format, a...)
+}
+
+// Warnf is like Successf but prints a warning (e.g. "! disk almost full").
+func Warnf(format string, a ...interface{}) {
+	printMessage(warnPrefix, format, a...)
+}
+
+// Infof is like Successf but prints an informational message (e.g.
+// "i using config ~/.app.yml").
+func Infof(format string, a ...interface{}) {
+	printMessage(infoPrefix, format, a...)
+}
+
+// Errorf is like Successf but prints an error (e.g. "✗ user not found"). Use
+// PrintError instead to print the error that terminates the application.
+func Errorf(format string, a ...interface{}) {
+	printMessage(errorPrefix, format, a...)
+}
+
+// printMessage prints a status message with the given prefix to the standard
+// error unless Quiet is true.
+func printMessage(prefix, format string, a ...interface{}) {
+	if Quiet {
+		return
+	}
+	writeMessage(prefix, format, a...)
+}
+
+// writeMessage prints a message with the given prefix to the standard error
+// even if Quiet is true.
+func writeMessage(prefix, format string, a ...interface{}) {
+	if !MessageColor.enabled(stderr) {
+		prefix = stripANSI(prefix)
+	}
+
+	msg := fmt.Sprintf(format, a...)
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	fmt.Fprint(stderr, prefix+" "+msg)
+}
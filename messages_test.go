@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessages(t *testing.T) {
+	out := new(bytes.Buffer)
+	stderr = out
+	defer func() { stderr = os.Stderr }()
+
+	Successf("created user %s", "alice")
+	Warnf("disk almost full\n")
+	Infof("using %d workers", 4)
+	Errorf("user %q not found", "bob")
+	assert.Equal(t, "✓ created user alice\n! disk almost full\ni using 4 workers\n✗ user \"bob\" not found\n", out.String())
+
+	defer func() { MessageColor = ColorAuto }()
+	MessageColor = ColorAlways
+	out.Reset()
+	Successf("done")
+	assert.Equal(t, "\x1b[32m✓\x1b[0m done\n", out.String())
+}
@@ -0,0 +1,589 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// An Option customizes how a value is encoded by Print and its variants. Each
+// option documents the encodings it applies to. Options that do not apply to
+// the selected encoding are ignored so the same options can be passed
+// regardless of the encoding the user has chosen.
+type Option func(*options)
+
+type options struct {
+	sortBy      string
+	sortDesc    bool
+	columns     []string
+	hideEmpty   bool
+	placeholder string
+	footer      map[string]string
+	rowNumbers  bool
+	color       ColorMode
+	headerStyle Style
+	cellStyle   func(row interface{}, column string) Style
+	border      BorderStyle
+
+	listSeparator string
+	listLimit     int
+
+	groupBy            string
+	spanningHeaders    bool
+	flushInterval      time.Duration
+	limit              int
+	limitMessage       string
+	streamLimitMessage string
+	maxWidth           int
+	cellReplacer       *strings.Replacer
+	showSecrets        bool
+
+	thousandsSeparator string
+	decimalSeparator   string
+
+	trueText, falseText   string
+	trueStyle, falseStyle Style
+
+	headerCase HeaderCase
+	title      string
+	minWidths  map[string]int
+	highlights []highlight
+	diffKey    string
+	merge      []string
+	splitBy    string
+	summary    summaryMode
+	maxDepth   int
+	noResults  string
+	tee        string
+
+	jsonIndent     string
+	jsonEscapeHTML *bool
+}
+
+// A highlight is a style that is applied to all rows that match a predicate.
+type highlight struct {
+	match func(row interface{}) bool
+	style Style
+}
+
+// defaultMaxDepth is the default value of the MaxDepth option.
+const defaultMaxDepth = 10
+
+// defaultCellReplacer escapes all characters that would break the alignment of
+// table columns.
+var defaultCellReplacer = strings.NewReplacer("\t", `\t`, "\r", `\r`, "\n", `\n`)
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		placeholder:        "-",
+		listSeparator:      ",",
+		flushInterval:      100 * time.Millisecond,
+		limitMessage:       "... %d more rows",
+		streamLimitMessage: "... more rows",
+		maxWidth:           -1,
+		cellReplacer:       defaultCellReplacer,
+
+		decimalSeparator: ".",
+		trueText:         "true",
+		falseText:        "false",
+		jsonIndent:       "    ",
+		maxDepth:         defaultMaxDepth,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// tableWidth returns the maximum width of a table that is written to w or 0 if
+// the width is not limited.
+func (o *options) tableWidth(w io.Writer) int {
+	if o.maxWidth >= 0 {
+		return o.maxWidth
+	}
+	return terminalWidth(w)
+}
+
+// useColor returns true if output that is written to w should be styled.
+func (o *options) useColor(w io.Writer) bool {
+	return o.color.enabled(w)
+}
+
+// SortBy sorts the rows of a slice or array by the given column before they
+// are printed. The column is matched case insensitively against the column
+// names as well as the names of the struct fields. Numbers are compared by
+// their value instead of lexicographically. If desc is true the rows are sorted
+// in descending order.
+//
+// This option only has an effect on the "table" encoding.
+func SortBy(column string, desc bool) Option {
+	return func(o *options) {
+		o.sortBy = column
+		o.sortDesc = desc
+	}
+}
+
+// Columns selects the columns that should be printed. Columns are printed in
+// the given order and are matched case insensitively against the column names
+// as well as the names of the struct fields. It is an error to select a column
+// that does not exist.
+//
+// This option only has an effect on the "table" encoding.
+func Columns(names ...string) Option {
+	return func(o *options) {
+		o.columns = names
+	}
+}
+
+// HideEmptyColumns omits all columns whose values are the zero value of
+// their type in every row. Empty slices or arrays are still printed with all
+// columns.
+//
+// This option only has an effect on the "table" encoding.
+func HideEmptyColumns() Option {
+	return func(o *options) {
+		o.hideEmpty = true
+	}
+}
+
+// Placeholder sets the string that is printed instead of empty values. Empty
+// values are nil pointers and interfaces, empty slices and maps, the zero
+// time.Time and all zero values of fields with the "omitempty" table tag
+// option. The default placeholder is "-".
+//
+// This option only has an effect on the "table" encoding.
+func Placeholder(s string) Option {
+	return func(o *options) {
+		o.placeholder = s
+	}
+}
+
+// Footer adds a footer row to the table. The keys of the given map are matched
+// case insensitively against the column names as well as the names of the
+// struct fields. Values set via this option take precedence over aggregates
+// computed via the "sum" and "count" table tag options.
+//
+// This option only has an effect on the "table" encoding.
+func Footer(cells map[string]string) Option {
+	return func(o *options) {
+		o.footer = cells
+	}
+}
+
+// RowNumbers prepends a "#" column to the table which contains the number of
+// each row, starting at 1. Rows are numbered after they have been sorted.
+//
+// This option only has an effect on the "table" encoding.
+func RowNumbers() Option {
+	return func(o *options) {
+		o.rowNumbers = true
+	}
+}
+
+// Color controls if the output is styled using ANSI escape sequences. By
+// default styles are only applied if the output is written to a terminal
+// (see ColorAuto).
+func Color(mode ColorMode) Option {
+	return func(o *options) {
+		o.color = mode
+	}
+}
+
+// HeaderStyle sets the style of the table header (e.g. Bold).
+//
+// This option only has an effect on the "table" encoding.
+func HeaderStyle(s Style) Option {
+	return func(o *options) {
+		o.headerStyle = s
+	}
+}
+
+// CellStyle sets a function which returns the style of each table cell. The
+// function is called with the value of the row and the name of the column the
+// cell belongs to.
+//
+// This option only has an effect on the "table" encoding.
+func CellStyle(fn func(row interface{}, column string) Style) Option {
+	return func(o *options) {
+		o.cellStyle = fn
+	}
+}
+
+// Border draws borders around all table cells using the given style (e.g.
+// BorderASCII or BorderUnicode). By default tables are printed without
+// borders.
+//
+// This option only has an effect on the "table" encoding.
+func Border(style BorderStyle) Option {
+	return func(o *options) {
+		o.border = style
+	}
+}
+
+// ListSeparator sets the separator which is used to join the elements of
+// slices, arrays and maps in a single table cell. The default separator is ",".
+//
+// This option only has an effect on the "table" encoding.
+func ListSeparator(sep string) Option {
+	return func(o *options) {
+		o.listSeparator = sep
+	}
+}
+
+// ListLimit sets the maximum number of elements of slices, arrays and maps that
+// are printed in a single table cell. If a value has more elements, the
+// remaining elements are omitted and their number is printed instead. By
+// default all elements are printed.
+//
+// This option only has an effect on the "table" encoding.
+func ListLimit(n int) Option {
+	return func(o *options) {
+		o.listLimit = n
+	}
+}
+
+// GroupBy groups the rows of a slice or array by the value of the given column.
+// The value of each group is printed once as section title above the rows of
+// the group and the column itself is omitted. Groups are printed in the order
+// of their first appearance so you may want to combine this option with
+// SortBy. The column is matched case insensitively against the column names
+// as well as the names of the struct fields.
+//
+// This option only has an effect on the "table" encoding.
+func GroupBy(column string) Option {
+	return func(o *options) {
+		o.groupBy = column
+	}
+}
+
+// SpanningHeaders prints the columns of flattened structs (see the "flatten"
+// table tag option) below a header that spans all columns of the struct.
+// Without this option the names of these columns are prefixed with the name
+// of the struct column instead (e.g. "OWNER.NAME").
+//
+// This option only has an effect on the "table" encoding.
+func SpanningHeaders() Option {
+	return func(o *options) {
+		o.spanningHeaders = true
+	}
+}
+
+// FlushInterval sets the maximum time that values which are received from a
+// channel are buffered before they are printed as part of a table. Buffering
+// values helps to align the columns of the table. The default interval is
+// 100ms.
+//
+// This option only has an effect on the "table" encoding.
+func FlushInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.flushInterval = d
+	}
+}
+
+// Limit prints at most n rows of a slice, array or channel followed by a
+// message which contains the number of omitted rows (see LimitMessage). Footer
+// aggregates are still computed over all rows. If n is zero or negative, all
+// rows are printed.
+//
+// No more values are received from a channel once the limit has been
+// exceeded, so channels may be unbounded. The number of omitted values of a
+// channel is unknown, so the message of StreamLimitMessage is printed instead.
+//
+// This option only has an effect on the "table" and "detail" encodings.
+func Limit(n int) Option {
+	return func(o *options) {
+		o.limit = n
+	}
+}
+
+// LimitMessage sets the message that is printed if rows have been omitted due
+// to the Limit option. The format must contain a single %d verb which is
+// replaced with the number of omitted rows. The default message is
+// "... %d more rows".
+//
+// This option only has an effect on the "table" and "detail" encodings.
+func LimitMessage(format string) Option {
+	return func(o *options) {
+		o.limitMessage = format
+	}
+}
+
+// StreamLimitMessage sets the message that is printed if values of a channel
+// have been omitted due to the Limit option. Unlike the message of
+// LimitMessage, it does not contain the number of omitted values since they
+// are not received. The default message is "... more rows".
+//
+// This option only has an effect on the "table" and "detail" encodings.
+func StreamLimitMessage(message string) Option {
+	return func(o *options) {
+		o.streamLimitMessage = message
+	}
+}
+
+// MaxWidth sets the maximum width of a table in characters. If a table is wider,
+// columns with a "priority" tag option are removed and the cells of the widest
+// remaining columns are truncated until the table fits. By default the maximum
+// width is the width of the terminal if the output is written to a terminal.
+// If n is zero or negative, the width of the table is not limited.
+//
+// This option only has an effect on the "table" encoding.
+func MaxWidth(n int) Option {
+	return func(o *options) {
+		if n < 0 {
+			n = 0
+		}
+		o.maxWidth = n
+	}
+}
+
+// CellReplacer sets the replacer that is applied to all cell values before they
+// are printed. By default tabs, carriage returns and newlines are escaped as
+// `\t`, `\r` and `\n` since they would otherwise break the alignment of the
+// columns. Newlines of fields with a "wrap" tag option are never replaced
+// because they are used to break the lines of the cell. If r is nil, the
+// cell values are printed unchanged.
+//
+// This option only has an effect on the "table" and "detail" encodings.
+func CellReplacer(r *strings.Replacer) Option {
+	return func(o *options) {
+		o.cellReplacer = r
+	}
+}
+
+// ShowSecrets prints the actual values of fields with a "redact" tag option
+// instead of masking them. This is meant to be enabled explicitly by the user
+// (e.g. via a --show-secrets flag).
+func ShowSecrets() Option {
+	return func(o *options) {
+		o.showSecrets = true
+	}
+}
+
+// NumberFormat sets the separators that are used to format numbers. The
+// thousands separator is inserted between each group of three digits of the
+// integer part (e.g. "1,234,567") and the decimal separator replaces the
+// decimal point of floating point values. Use NumberFormat(".", ",") for
+// German or NumberFormat(",", ".") for English number formatting. By default
+// numbers are not grouped and use a decimal point. The number of decimal
+// places can be set via the "decimals" tag option.
+//
+// This option only has an effect on the "table" and "detail" encodings.
+func NumberFormat(thousands, decimal string) Option {
+	return func(o *options) {
+		o.thousandsSeparator = thousands
+		o.decimalSeparator = decimal
+	}
+}
+
+// Booleans sets the text that is printed for boolean values (e.g. "yes" and
+// "no" or "✓" and "✗"). The "bool" tag option can be used to set the text of
+// individual fields (e.g. `table:",bool=yes/no"`). By default booleans are
+// printed as "true" and "false".
+//
+// This option only has an effect on the "table" and "detail" encodings.
+func Booleans(trueText, falseText string) Option {
+	return func(o *options) {
+		o.trueText = trueText
+		o.falseText = falseText
+	}
+}
+
+// BooleanStyles sets the styles of cells that contain boolean values. Cells
+// that are styled via the CellStyle option keep their style. Styles are only
+// applied if colors are enabled (see the Color option).
+//
+// This option only has an effect on the "table" encoding.
+func BooleanStyles(trueStyle, falseStyle Style) Option {
+	return func(o *options) {
+		o.trueStyle = trueStyle
+		o.falseStyle = falseStyle
+	}
+}
+
+// HeaderCasing sets how the names of struct fields are converted to column
+// names (e.g. HeaderTitleCase). Column names that are set explicitly via the
+// "table" tag are printed as they are. By default field names are converted to
+// upper case.
+//
+// Note that columns are selected and matched by their converted names (e.g. in
+// the Columns and SortBy options) as well as by their field names.
+//
+// This option only has an effect on the "table" and "detail" encodings.
+func HeaderCasing(c HeaderCase) Option {
+	return func(o *options) {
+		o.headerCase = c
+	}
+}
+
+// Title prints the given title on its own line above the table. It is styled
+// like the header of the table. Use this to label multiple tables that are
+// printed by the same command.
+//
+// This option only has an effect on the "table" and "detail" encodings.
+func Title(title string) Option {
+	return func(o *options) {
+		o.title = title
+	}
+}
+
+// MinColumnWidth sets the minimum width of the content of a column so the
+// layout of the table does not change between invocations if the values of a
+// column have different lengths (e.g. when the output is refreshed
+// periodically). The column is matched like in the Columns option. The minimum
+// width can also be set via the "minwidth" tag option (e.g.
+// `table:",minwidth=20"`).
+//
+// This option only has an effect on the "table" encoding.
+func MinColumnWidth(column string, width int) Option {
+	return func(o *options) {
+		if o.minWidths == nil {
+			o.minWidths = map[string]int{}
+		}
+		o.minWidths[column] = width
+	}
+}
+
+// HighlightIf styles all rows of a table for which match returns true (e.g. to
+// make failed health checks stand out). The function receives each element of
+// the printed slice, array or channel. This option may be passed multiple
+// times in which case the styles of all matching highlights are combined. The
+// styles of individual cells (see CellStyle) are applied on top of the style of
+// the row. Styles are only applied if colors are enabled (see the Color option).
+//
+// This option only has an effect on the "table" encoding.
+func HighlightIf(match func(row interface{}) bool, style Style) Option {
+	return func(o *options) {
+		o.highlights = append(o.highlights, highlight{match: match, style: style})
+	}
+}
+
+// DiffKey sets the column that is used to match the rows of the old and new
+// values of PrintTableDiff. The column is matched like in the Columns option and
+// does not have to be printed. By default the first column is used.
+//
+// This option only has an effect on PrintTableDiff.
+func DiffKey(column string) Option {
+	return func(o *options) {
+		o.diffKey = column
+	}
+}
+
+// MergeRepeated prints the value of a column only once if consecutive rows
+// have the same value in this column. The cells of the following rows are left
+// blank which reduces visual noise in sorted or grouped tables. A cell is only
+// left blank if all merged columns to its left are blank as well. If no
+// columns are given, all columns are merged. Columns can also be merged via
+// the "merge" tag option.
+//
+// This option only has an effect on the "table" encoding.
+func MergeRepeated(columns ...string) Option {
+	return func(o *options) {
+		o.merge = append([]string{}, columns...)
+	}
+}
+
+// SplitBy partitions the rows by the value of the given column and prints one
+// table per partition. Each table has a title that contains the value of the
+// column and the column itself is not printed. Partitions are ordered by the
+// first appearance of their value. In contrast to GroupBy, all other options
+// apply to each table individually, e.g. HideEmptyColumns hides the columns
+// that are empty in a partition. It is an error to split by a column that does
+// not exist. Streams cannot be split.
+//
+// This option only has an effect on the "table" encoding.
+func SplitBy(column string) Option {
+	return func(o *options) {
+		o.splitBy = column
+	}
+}
+
+// NoResults prints the given message instead of an empty table if there are no
+// rows to print (e.g. because the value is an empty slice, a nil pointer or
+// nil). By default only the header of an empty table is printed.
+//
+// This option only has an effect on the "table" and "detail" encodings.
+func NoResults(message string) Option {
+	return func(o *options) {
+		o.noResults = message
+	}
+}
+
+// Tee writes the value to the file at the given path in addition to the
+// writer that it is printed to, so users can look at the results and save
+// them at the same time. The encoding of the file is inferred from its
+// extension like PrintFile does (e.g. "results.json") and defaults to the
+// encoding of the output. Files with a ".gz" extension are compressed. The
+// file is written atomically after the output has been printed. Streams are
+// therefore collected before they are printed. If path is empty, no file is
+// written.
+func Tee(path string) Option {
+	return func(o *options) {
+		o.tee = path
+	}
+}
+
+// Summary prints a second table below the table which contains the count,
+// minimum, maximum and mean of all numeric columns. The count is the number of
+// non-empty values of a column. Statistics are computed over all rows, i.e.
+// they are not affected by the Limit option.
+//
+// This option only has an effect on the "table" encoding.
+func Summary() Option {
+	return func(o *options) {
+		o.summary = summaryAppend
+	}
+}
+
+// SummaryOnly is like Summary but only prints the summary table instead of
+// the rows.
+//
+// This option only has an effect on the "table" encoding.
+func SummaryOnly() Option {
+	return func(o *options) {
+		o.summary = summaryOnly
+	}
+}
+
+// JSONIndent sets the string that is used to indent nested JSON values. By
+// default four spaces are used. If indent is empty, each value is printed on a
+// single line.
+//
+// This option only has an effect on the "json" encoding.
+func JSONIndent(indent string) Option {
+	return func(o *options) {
+		o.jsonIndent = indent
+	}
+}
+
+// JSONEscapeHTML controls whether problematic HTML characters are escaped
+// inside JSON quoted strings. It overrides the JSONHTMLEscape variable.
+//
+// This option only has an effect on the "json" encoding.
+func JSONEscapeHTML(escape bool) Option {
+	return func(o *options) {
+		o.jsonEscapeHTML = &escape
+	}
+}
+
+// MaxDepth limits how deeply nested values are rendered. Elements of nested
+// slices, arrays and maps in table cells that are nested deeper than n levels
+// are printed as "…" so self-referential values (e.g. an []interface{} that
+// contains itself) cannot cause infinite loops or gigantic cells. Structs
+// cannot be flattened deeper than n levels either (see the "flatten" tag
+// option). The default maximum depth is 10.
+//
+// This option only has an effect on the "table", "detail" and "csv"
+// encodings.
+func MaxDepth(n int) Option {
+	return func(o *options) {
+		o.maxDepth = n
+	}
+}
+
+// escapeHTML returns true if HTML characters should be escaped in JSON.
+func (o *options) escapeHTML() bool {
+	if o.jsonEscapeHTML != nil {
+		return *o.jsonEscapeHTML
+	}
+	return JSONHTMLEscape
+}
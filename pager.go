@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultPager is the pager that is used if the PAGER environment variable is
+// not set. The -R flag makes less print ANSI colors instead of escaping them.
+const defaultPager = "less -R"
+
+// PrintPaged is like Print but pipes the output through a pager if it is
+// written to a terminal and does not fit onto a single screen. This way the
+// first lines of long tables do not scroll out of view. The pager is taken
+// from the PAGER environment variable and defaults to "less -R". If the
+// standard output is not a terminal or the pager cannot be started, the output
+// is printed exactly like Print does.
+//
+// Note that the output has to be buffered to count its lines so streams are
+// not printed before the channel is closed.
+func PrintPaged(encoding string, value interface{}, opts ...Option) error {
+	w := os.Stdout
+	if !isTerminal(w) {
+		return PrintWriter(encoding, value, w, opts...)
+	}
+
+	if encoding == "" {
+		encoding = defaultEncoding(w)
+	}
+
+	// The output is written to a buffer so the colors and the width of a
+	// table have to be determined for the terminal upfront. They can still
+	// be overridden by the given options.
+	color := ColorNever
+	if ColorAuto.enabled(w) {
+		color = ColorAlways
+	}
+	opts = append([]Option{Color(color), MaxWidth(terminalWidth(w))}, opts...)
+
+	buf := new(bytes.Buffer)
+	if err := PrintWriter(encoding, value, buf, opts...); err != nil {
+		return err
+	}
+
+	return page(buf.Bytes(), w, terminalHeight(w))
+}
+
+// page writes out to w. If out has more lines than the given height, it is
+// written via the pager instead.
+func page(out []byte, w io.Writer, height int) error {
+	if height <= 0 || bytes.Count(out, []byte("\n")) < height {
+		_, err := w.Write(out)
+		return err
+	}
+
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = strings.Fields(defaultPager)
+	}
+
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = bytes.NewReader(out)
+	cmd.Stdout = w
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		_, err = w.Write(out)
+		return err
+	}
+
+	return cmd.Wait()
+}
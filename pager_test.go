@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPage(t *testing.T) {
+	defer os.Setenv("PAGER", os.Getenv("PAGER"))
+
+	cases := map[string]struct {
+		pager    string
+		height   int
+		expected string
+	}{
+		"fits": {
+			pager:    "tr a-z A-Z",
+			height:   4,
+			expected: "a\nb\nc\n",
+		},
+		"no terminal": {
+			pager:    "tr a-z A-Z",
+			height:   0,
+			expected: "a\nb\nc\n",
+		},
+		"paged": {
+			pager:    "tr a-z A-Z",
+			height:   3,
+			expected: "A\nB\nC\n",
+		},
+		"unknown pager": {
+			pager:    "this-pager-does-not-exist",
+			height:   1,
+			expected: "a\nb\nc\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.Setenv("PAGER", c.pager))
+			out := new(bytes.Buffer)
+			require.NoError(t, page([]byte("a\nb\nc\n"), out, c.height))
+			assert.Equal(t, c.expected, out.String())
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrInterrupted is returned by ReadPassword if the user pressed Ctrl+C.
+var ErrInterrupted = errors.New("interrupted")
+
+// ReadPassword writes the prompt to stderr and reads a single line from stdin
+// without echoing the typed characters. The terminal is put into raw mode
+// while reading and its previous state is restored before the function
+// returns, even if the context is canceled.
+//
+// Because the terminal does not send signals in raw mode, pressing Ctrl+C is
+// handled by this function: it returns ErrInterrupted and propagates SIGINT to
+// all contexts that were created via Context (see ReceiveSignal).
+//
+// If stdin is not a terminal, the password is read like ReadLineErr reads a
+// line, so it can be piped to the application.
+func ReadPassword(ctx context.Context, prompt string) (string, error) {
+	return readPassword(ctx, prompt, nil)
+}
+
+// ReadPasswordMasked reads a password like ReadPassword does but echoes an
+// asterisk for each typed character so the user gets visual feedback.
+func ReadPasswordMasked(ctx context.Context, prompt string) (string, error) {
+	return readPassword(ctx, prompt, stderr)
+}
+
+// ReadNewPassword asks for a new password twice like ReadPassword does and
+// returns it if both passwords are equal. If they are not equal, if the
+// password is empty or if it is rejected by the function that is set via the
+// PasswordPolicy option, the error is printed like Errorf does (even if Quiet
+// is true) and the user is asked again. The MaxAttempts and RetryDelay
+// options limit how often this happens.
+func ReadNewPassword(ctx context.Context, opts ...PromptOption) (string, error) {
+	o := newPromptOptions(opts)
+	for attempt := 1; ; attempt++ {
+		password, err := ReadPassword(ctx, "New password: ")
+		if err != nil {
+			return "", err
+		}
+
+		invalid := checkNewPassword(password, o)
+		if invalid == nil {
+			retyped, err := ReadPassword(ctx, "Retype new password: ")
+			if err != nil {
+				return "", err
+			}
+			if retyped == password {
+				return password, nil
+			}
+			invalid = errors.New("passwords do not match")
+		}
+
+		if err := o.retry(ctx, attempt, invalid); err != nil {
+			return "", err
+		}
+	}
+}
+
+// checkNewPassword returns an error if the password is empty or is rejected
+// by the function that is set via the PasswordPolicy option.
+func checkNewPassword(password string, o *promptOptions) error {
+	if password == "" {
+		return errors.New("password must not be empty")
+	}
+	if o.passwordPolicy != nil {
+		return o.passwordPolicy(password)
+	}
+	return nil
+}
+
+// readPassword implements ReadPassword. If mask is not nil, an asterisk is
+// written to it for each typed character.
+func readPassword(ctx context.Context, prompt string, mask io.Writer) (string, error) {
+	fmt.Fprint(stderr, prompt)
+	defer fmt.Fprintln(stderr)
+
+	restore, ok, err := makeStdinRaw()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return ReadLineErr(ctx)
+	}
+	defer restore()
+
+	return readSecret(ctx, stdinLineReader(), mask)
+}
+
+// readSecret reads keys from a terminal in raw mode until enter is pressed.
+// Backspace removes the last character and Ctrl+U removes all of them. Other
+// keys such as the arrow keys are ignored. If mask is not nil, the characters
+// are echoed as asterisks.
+func readSecret(ctx context.Context, lr *lineReader, mask io.Writer) (string, error) {
+	// erase removes n asterisks from the terminal.
+	erase := func(n int) {
+		if mask != nil {
+			fmt.Fprint(mask, strings.Repeat("\b \b", n))
+		}
+	}
+
+	var secret []rune
+	err := readKeyPresses(ctx, lr, func(k keyPress) (bool, error) {
+		switch k.Key {
+		case keyEnter:
+			return true, nil
+		case keyInterrupt:
+			ReceiveSignal(os.Interrupt)
+			return false, ErrInterrupted
+		case keyEOF:
+			if len(secret) == 0 {
+				return false, io.EOF
+			}
+		case keyBackspace:
+			if len(secret) > 0 {
+				secret = secret[:len(secret)-1]
+				erase(1)
+			}
+		case keyClear:
+			erase(len(secret))
+			secret = secret[:0]
+		case keyRune:
+			secret = append(secret, k.R)
+			if mask != nil {
+				fmt.Fprint(mask, "*")
+			}
+		}
+		return false, nil
+	})
+
+	if err == io.EOF && len(secret) > 0 {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
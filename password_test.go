@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPassword_NotATerminal(t *testing.T) {
+	defer func() { stdin, stderr = os.Stdin, os.Stderr }()
+	stdin = strings.NewReader("secret\nnext line\n")
+	out := new(bytes.Buffer)
+	stderr = out
+
+	password, err := ReadPassword(context.Background(), "Password: ")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", password)
+	assert.Equal(t, "Password: \n", out.String())
+}
+
+func TestReadSecret(t *testing.T) {
+	ctx := context.Background()
+	lr := newLineReader(strings.NewReader("sex\x7fcrät\x7f\x7fet\rabc\x15pw\r\x03"))
+
+	secret, err := readSecret(ctx, lr, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", secret)
+
+	secret, err = readSecret(ctx, lr, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "pw", secret)
+
+	_, err = readSecret(ctx, lr, nil)
+	assert.Equal(t, ErrInterrupted, err)
+
+	_, err = readSecret(ctx, lr, nil)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadSecret_Masked(t *testing.T) {
+	lr := newLineReader(strings.NewReader("pä\x7fss\x15pw\r"))
+	out := new(bytes.Buffer)
+
+	secret, err := readSecret(context.Background(), lr, out)
+	assert.NoError(t, err)
+	assert.Equal(t, "pw", secret)
+	assert.Equal(t, "**\b \b**\b \b\b \b\b \b**", out.String())
+}
+
+func TestReadSecret_EscapeSequences(t *testing.T) {
+	lr := newLineReader(strings.NewReader("se\x1b[Acr\x1b[D\x1b[3~et\r"))
+	out := new(bytes.Buffer)
+
+	secret, err := readSecret(context.Background(), lr, out)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", secret)
+	assert.Equal(t, "******", out.String())
+}
+
+func TestReadNewPassword(t *testing.T) {
+	out := mockPrompt(t, "\nshort\nlong enough\nlong enuogh\nlong enough\nlong enough\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+
+	validate := func(password string) error {
+		if len(password) < 8 {
+			return errors.New("password must have at least 8 characters")
+		}
+		return nil
+	}
+
+	password, err := ReadNewPassword(context.Background(), PasswordPolicy(validate))
+	assert.NoError(t, err)
+	assert.Equal(t, "long enough", password)
+	assert.Equal(t, "New password: \n✗ password must not be empty\n"+
+		"New password: \n✗ password must have at least 8 characters\n"+
+		"New password: \nRetype new password: \n✗ passwords do not match\n"+
+		"New password: \nRetype new password: \n", out.String())
+}
+
+func TestReadNewPassword_MaxAttempts(t *testing.T) {
+	mockPrompt(t, "a\nb\nc\nd\n")
+
+	_, err := ReadNewPassword(context.Background(), MaxAttempts(2))
+	assert.True(t, errors.Is(err, ErrTooManyAttempts))
+	assert.EqualError(t, err, "too many invalid answers: passwords do not match")
+}
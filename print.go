@@ -1,15 +1,14 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"reflect"
-	"bytes"
-	"sort"
 	"strings"
-	"text/tabwriter"
 
 	"gopkg.in/yaml.v2"
 )
@@ -21,46 +20,292 @@ var (
 	//
 	// By default this is disabled to not interfere with readability of the
 	// output. If you are rendering output in an HTML context you should enable
-	// this feature.
+	// this feature. The JSONEscapeHTML option can be used to override this
+	// setting for individual calls.
 	JSONHTMLEscape = false
+
+	// EncodingEnv is the name of the environment variable that contains the
+	// encoding which is used if the encoding passed to Print is empty. This
+	// lets users select their preferred encoding once (e.g. via
+	// "export CLI_OUTPUT=json") instead of passing it to each invocation of an
+	// application. Applications should set this to a name that is specific to
+	// them (e.g. "MYAPP_OUTPUT"). Set it to the empty string to disable this
+	// feature.
+	EncodingEnv = "CLI_OUTPUT"
+
+	// PipedEncoding is the encoding which is used if the encoding passed to
+	// Print is empty, the environment variable named by EncodingEnv is not set
+	// and the output is not written to a terminal (e.g. because it is piped
+	// into another program). Tables are meant to be read by humans while
+	// other programs are better served with a machine readable encoding. If
+	// this is empty, the "table" encoding is used for all output.
+	PipedEncoding = "json"
+
+	// Quiet suppresses all output except for the identifiers of the printed
+	// values (like "docker ps -q") so they can be passed on to other commands
+	// in scripts. If this is true, the "table" and "detail" encodings only
+	// print the identifier of each row on its own line without a header and
+	// the status messages of Successf, Warnf, Infof and Errorf are not
+	// printed. The identifier is the field with an "id" option in its "table"
+	// tag, the field with the column name "ID" or the first column. The rows
+	// are sorted and limited like the rows of the table (see SortBy and
+	// Limit). All other encodings are not affected.
+	Quiet = false
 )
 
+// stderr is the io.Writer that diagnostics are written to. This is a variable
+// so we can mock it in tests.
+var stderr io.Writer = os.Stderr
+
 // Print encodes the value using the given encoding and then prints it to the
-// standard output. Accepted encodings are "json", "yml", "yaml", "table" and
-// "raw". If encoding is the empty string this function defaults to "table"
-// encoding.
+// standard output. Accepted encodings are "json", "yml", "yaml", "table",
+// "detail", "csv" and "raw". If encoding is the empty string this function
+// uses the encoding from the environment variable that is named by EncodingEnv.
+// If it is not set, the "table" encoding is used if the output is written to a
+// terminal and the PipedEncoding otherwise.
 //
 // Usually the encoding is controlled via command line flags of your application
 // so the user can select in what format the output should be returned.
 //
-// Accepted encodings
+// # Accepted encodings
+//
+// "table":  value is printed via a tab writer (see below)
+// "detail": like "table" but each field is printed on its own line
+// "json":   value is printed as indented JSON
+// "yaml":   value is printed as YAML
+// "csv":    like "table" but the rows are printed as comma separated values
+// "raw":    value is printed via fmt.Println
 //
-// "table": value is printed via a tab writer (see below)
-// "json":  value is printed as indented JSON
-// "yaml":  value is printed as YAML
-// "raw":   value is printed via fmt.Println
+// The "json" and "yaml" encodings highlight keys and values if colors are
+// enabled (see the Color option), e.g. because the output is written to a
+// terminal.
 //
-// Table encoding
+// # Table encoding
 //
 // If the "table" encoding is used, the reflection API is used to print all
 // exported fields of the value via a tab writer. The columns will be the
-// UPPERCASE field names or whatever you set in the "table" tag of the
-// corresponding field. Field names with a "table" tag set to "-" are omitted.
-// When the "table" encoding is used the value must either be a struct, pointer
-// to a struct, a slice or an array.
-func Print(encoding string, value interface{}) error {
-	return PrintWriter(encoding, value, os.Stdout)
+// UPPERCASE field names (see the HeaderCasing option) or whatever you set in
+// the "table" tag of the corresponding field. Field names with a "table" tag
+// set to "-" are omitted.
+//
+// The column name in the "table" tag may be followed by a comma separated list
+// of options (e.g. `table:"MESSAGE,wrap=40"`). The following options are
+// supported:
+//
+//   - "wrap=N": the cell content is wrapped onto multiple lines of at most N
+//     characters. The other columns of the row stay aligned.
+//   - "omitempty": zero values are printed as a placeholder instead of their
+//     actual value (e.g. 0 or false). See also the Placeholder option.
+//   - "sum": the sum of all values of a numeric field is printed in a footer
+//     row below the table.
+//   - "count": the number of non-zero values of the field is printed in a
+//     footer row below the table.
+//   - "format=LAYOUT": a time.Time is formatted using the given layout (see
+//     time.Time.Format). Times are formatted as RFC 3339 by default. Note that
+//     the layout must not contain any commas.
+//   - "since": a time.Time is printed as the time that has passed since then
+//     (e.g. "3h ago").
+//   - "precision=DURATION": a time.Duration is rounded to the given precision
+//     (e.g. "precision=1s"). By default durations are rounded so they contain
+//     at most two units (e.g. "1h12m").
+//   - "bytes": a numeric value is printed as a byte size using binary units
+//     (e.g. "1.2 GiB").
+//   - "flatten": the fields of a nested struct are printed as individual
+//     columns. Their names are prefixed with the name of the struct column
+//     (e.g. "OWNER.NAME"). See also the SpanningHeaders option.
+//   - "order=N": columns with an order are printed first in ascending order,
+//     followed by all other columns in the order of the struct fields. This
+//     way the columns can be arranged without reordering the struct fields. See
+//     also the Columns option.
+//   - "priority=N": the column is removed if the table does not fit into the
+//     width of the terminal. Columns with the highest priority are removed
+//     first. See also the MaxWidth option.
+//   - "decimals=N": a floating point value is printed with N decimal places.
+//     See also the NumberFormat option.
+//   - "bool=TRUE/FALSE": a boolean is printed as the given text (e.g.
+//     "bool=yes/no"). See also the Booleans option.
+//   - "minwidth=N": the column is at least N characters wide. See also the
+//     MinColumnWidth option.
+//   - "merge": repeated values in consecutive rows are only printed once.
+//     See also the MergeRepeated option.
+//   - "redact": the value is a secret and is printed as "****" (see below).
+//   - "id": the field identifies its row and is printed in quiet mode (see
+//     Quiet).
+//
+// Values that implement the TableMarshaler interface provide their header and
+// rows themselves. The tag options above as well as options that refer to
+// columns (e.g. SortBy or Columns) do not apply to them.
+//
+// Pointers are dereferenced before they are printed. Nil pointers as well as
+// other empty values are printed as placeholder (see the Placeholder option).
+// Field values that implement the TableCell interface are printed via their
+// TableCell method. Otherwise values that implement fmt.Stringer or
+// encoding.TextMarshaler are printed via their String or MarshalText methods.
+// Slices and arrays are printed as a comma separated list of their elements
+// and maps are printed as comma separated "key=value" pairs which are sorted by
+// key (see the ListSeparator and ListLimit options). All other values are
+// printed via fmt.Sprint.
+//
+// If a table is wider than the terminal (see the MaxWidth option) and does not
+// fit after removing its columns with a priority, the cells of the widest
+// columns are truncated and end with an ellipsis.
+//
+// Maps are printed as a table with one row per entry which is sorted by key.
+// The first column contains the keys. If the values are structs, their fields
+// are printed as the other columns. Otherwise the values are printed in a
+// single "VALUE" column. All other values that are neither structs, slices,
+// arrays nor channels are printed via fmt.Println.
+//
+// Nil pointers to structs and empty or nil slices are printed as a table that
+// only contains a header (see the NoResults option). Printing nil does not
+// produce any output.
+//
+// The elements of a slice of interfaces (e.g. []interface{}) are printed as
+// table rows if all of them are structs of the same type or pointers to such
+// structs. Nil elements are printed as rows of placeholders. If the elements
+// have different types, each element is printed on its own line via
+// fmt.Println.
+//
+// # Secrets
+//
+// Fields with a "redact" option in their "table" tag are masked by all
+// encodings so secrets such as API tokens do not leak into terminal scrollback
+// or logs. Table cells are printed as "****". For the other encodings a copy
+// of the value is printed in which redacted strings are replaced with "****"
+// and all other redacted values are set to their zero value. Empty values are
+// not masked. Note that redacted fields of types that implement their own
+// marshaling methods cannot be masked by the "json" and "yaml" encodings.
+// Use the ShowSecrets option to print the actual values.
+//
+// # CSV encoding
+//
+// The "csv" encoding prints the same columns and cells as the "table"
+// encoding as comma separated values with a header row. Options that only
+// affect the layout of a table (e.g. wrapping, borders, styles or footers)
+// are ignored and empty values are printed as empty fields instead of a
+// placeholder.
+//
+// # Detail encoding
+//
+// The "detail" encoding prints each field of a struct on its own line as
+// "NAME: value" which is more readable than a very wide table with a single
+// row. Slices and arrays are printed as one such block per element, separated
+// by an empty line. Column names and values are determined exactly like in the
+// "table" encoding.
+//
+// # Streams
+//
+// If the value is a channel, all values are received from the channel until it
+// is closed. The "table" and "detail" encodings print the values as soon as
+// they are received (see the FlushInterval option) so users can see the first
+// results of long running operations immediately. The SortBy, GroupBy and
+// SplitBy options cannot be used with streams and no table borders or footers
+// are printed. The "json" encoding prints a JSON array whose elements are
+// written as soon as they are received. All other encodings print the values as
+// a list once the channel is closed.
+//
+// Slices and arrays are encoded element by element by the "json" encoding so
+// very large values do not have to be held in memory as a whole once they are
+// encoded.
+//
+// Iterators (i.e. iter.Seq and iter.Seq2) are printed like streams of the
+// values they yield so they do not have to be collected into a slice first. The
+// keys of an iter.Seq2 are not printed.
+//
+// # Views
+//
+// Default options for the values of a type can be registered via
+// RegisterView. They are applied before the options that are passed to Print.
+//
+// The output can be further customized by passing any number of options.
+func Print(encoding string, value interface{}, opts ...Option) error {
+	return PrintWriter(encoding, value, os.Stdout, opts...)
+}
+
+// PrintErr is like Print but prints the value to the standard error. Use this
+// for diagnostics so they do not interfere with the primary output of an
+// application if it is piped into another program.
+func PrintErr(encoding string, value interface{}, opts ...Option) error {
+	return PrintWriter(encoding, value, stderr, opts...)
+}
+
+// Eprintf formats according to a format specifier and writes the message to
+// the standard error. A newline is appended if the message does not end with
+// one.
+func Eprintf(format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	fmt.Fprint(stderr, msg)
+}
+
+// Eprintln formats its operands like fmt.Println and writes the message to the
+// standard error.
+func Eprintln(a ...interface{}) {
+	fmt.Fprintln(stderr, a...)
 }
 
 // PrintWriter is like Print but lets the caller inject an io.Writer.
-func PrintWriter(encoding string, value interface{}, w io.Writer) error {
-	switch strings.ToLower(encoding) {
+func PrintWriter(encoding string, value interface{}, w io.Writer, opts ...Option) error {
+	encoding = strings.ToLower(encoding)
+	if encoding == "" {
+		encoding = defaultEncoding(w)
+	}
+
+	if isIterator(value) {
+		c, stop := iterate(value)
+		defer stop()
+		value = c
+	}
+
+	if view := viewOptions(value); view != nil {
+		opts = append(view, opts...)
+	}
+	o := newOptions(opts)
+	if o.tee != "" {
+		return printTee(encoding, value, w, o.tee, opts)
+	}
+
+	if !o.useColor(w) {
+		w = StripANSIWriter(w)
+	}
+
+	if Quiet && (encoding == "table" || encoding == "detail") {
+		return printIDs(value, w, o)
+	}
+
+	if isStream(value) {
+		switch encoding {
+		case "table":
+			return printTableStream(value, w, o)
+		case "detail":
+			return printDetailStream(value, w, o)
+		case "json":
+			return printJSONStream(value, w, o)
+		default:
+			value = collectStream(value)
+		}
+	}
+
+	if !o.showSecrets {
+		switch encoding {
+		case "json", "yml", "yaml", "raw":
+			value = redact(value)
+		}
+	}
+
+	switch encoding {
 	case "json":
-		return printJSON(value, w)
+		return printJSON(value, w, o)
 	case "yml", "yaml":
-		return printYAML(value, w)
-	case "table", "":
-		return printTable(value, w)
+		return printYAML(value, w, o)
+	case "table":
+		return printTable(value, w, o)
+	case "detail":
+		return printDetail(value, w, o)
+	case "csv":
+		return printCSV(value, w, o)
 	case "raw":
 		return printRaw(value, w)
 	default:
@@ -69,141 +314,179 @@ func PrintWriter(encoding string, value interface{}, w io.Writer) error {
 }
 
 // MustPrint is exactly like Print but panics if an error occurs.
-func MustPrint(encoding string, i interface{}) {
-	err := Print(encoding, i)
+func MustPrint(encoding string, i interface{}, opts ...Option) {
+	MustPrintWriter(encoding, i, os.Stdout, opts...)
+}
+
+// MustPrintWriter is exactly like PrintWriter but panics if an error occurs.
+func MustPrintWriter(encoding string, i interface{}, w io.Writer, opts ...Option) {
+	err := PrintWriter(encoding, i, w, opts...)
 	if err != nil {
 		panic(err)
 	}
 }
 
+// defaultEncoding returns the encoding that is used if the encoding passed to
+// Print is empty and the output is written to w.
+func defaultEncoding(w io.Writer) string {
+	if EncodingEnv != "" {
+		if encoding := os.Getenv(EncodingEnv); encoding != "" {
+			return strings.ToLower(encoding)
+		}
+	}
+
+	if PipedEncoding != "" && !isTerminal(w) {
+		return strings.ToLower(PipedEncoding)
+	}
+
+	return "table"
+}
+
 func printRaw(i interface{}, w io.Writer) error {
 	_, err := fmt.Fprintln(w, i)
 	return err
 }
 
-func printJSON(i interface{}, w io.Writer) error {
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "    ")
-	enc.SetEscapeHTML(JSONHTMLEscape)
-	return enc.Encode(i)
-}
+func printJSON(i interface{}, w io.Writer, opts *options) error {
+	if v := reflect.ValueOf(i); isJSONArray(v) {
+		return writeJSONArray(w, opts, func(yield func(interface{}) error) error {
+			for n := 0; n < v.Len(); n++ {
+				if err := yield(v.Index(n).Interface()); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
 
-func printYAML(i interface{}, w io.Writer) error {
-	out, err := yaml.Marshal(i)
-	if err != nil {
+	if !opts.useColor(w) {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", opts.jsonIndent)
+		enc.SetEscapeHTML(opts.escapeHTML())
+		return enc.Encode(i)
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetIndent("", opts.jsonIndent)
+	enc.SetEscapeHTML(opts.escapeHTML())
+	if err := enc.Encode(i); err != nil {
 		return err
 	}
 
-	_, err = fmt.Fprintln(w, string(out))
+	_, err := w.Write(highlightJSON(buf.Bytes()))
 	return err
 }
 
-func printTable(v interface{}, w io.Writer) error {
-	val := reflect.ValueOf(v)
-	t := val.Type()
-
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
-	}
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
 
-	var isArray bool
-	if t.Kind() == reflect.Array || t.Kind() == reflect.Slice {
-		isArray = true
-		t = t.Elem()
-	}
+// printJSONStream prints all values that are received from the channel ch as
+// a JSON array. The elements are written as soon as they are received. If the
+// output cannot be written, no more values are received from the channel.
+func printJSONStream(ch interface{}, w io.Writer, opts *options) error {
+	c := reflect.ValueOf(ch)
+	return writeJSONArray(w, opts, func(yield func(interface{}) error) error {
+		for {
+			v, ok := c.Recv()
+			if !ok {
+				return nil
+			}
 
-	if t.Kind() != reflect.Struct {
-		if isArray {
-			for i := 0; i < val.Len(); i++ {
-				_, err := fmt.Fprintln(w, val.Index(i))
-				if err != nil {
-					return err
-				}
+			value := v.Interface()
+			if !opts.showSecrets {
+				value = redact(value)
+			}
+			if err := yield(value); err != nil {
+				return err
 			}
-			return nil
 		}
-		return fmt.Errorf("cannot print type %T as table (kind %v)", v, t.Kind())
-	}
+	})
+}
 
-	type field struct {
-		Name  string
-		Index int
+// isJSONArray returns true if v is a slice or an array that is encoded as JSON
+// array by the encoding/json package and can therefore be encoded element by
+// element.
+func isJSONArray(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return false
+		}
+	case reflect.Array:
+	default:
+		return false
 	}
 
-	var fields []field
-	var header string
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		name := strings.ToUpper(f.Name)
+	_, isMarshaler := implements(v, jsonMarshalerType)
+	_, isTextMarshaler := implements(v, textMarshalerType)
+	return !isMarshaler && !isTextMarshaler
+}
 
-		if t := f.Tag.Get("table"); t != "" {
-			if t == "-" {
-				continue
-			}
-			name = t
+// writeJSONArray writes the elements that are passed to yield by the function
+// each as a JSON array to w. The elements are encoded one by one so the output
+// does not have to be held in memory. The result is the same as encoding the
+// whole array at once.
+func writeJSONArray(w io.Writer, opts *options, each func(yield func(interface{}) error) error) error {
+	out := bufio.NewWriter(w)
+	indent := opts.jsonIndent
+	color := opts.useColor(w)
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetIndent(indent, indent)
+	enc.SetEscapeHTML(opts.escapeHTML())
+
+	var n int
+	err := each(func(v interface{}) error {
+		buf.Reset()
+		if err := enc.Encode(v); err != nil {
+			return err
 		}
 
-		fields = append(fields, field{Name: name, Index: i})
-		header += name + "\t"
-	}
-
-	records := []map[string]string{}
-	if isArray {
-		for i := 0; i < val.Len(); i++ {
-			rr := map[string]string{}
-			for _, f := range fields {
-				elem := val.Index(i).Field(f.Index).Interface()
-				switch x := elem.(type) {
-				case map[string]string:
-					rr[f.Name] = stringMap(x)
-				default:
-					rr[f.Name] = fmt.Sprint(elem)
-				}
-			}
-			records = append(records, rr)
+		if n == 0 {
+			out.WriteString("[")
+		} else {
+			out.WriteString(",")
 		}
-	} else {
-		rr := map[string]string{}
-		for _, f := range fields {
-			rr[f.Name] = fmt.Sprint(val.Field(f.Index).Interface())
+		if indent != "" {
+			out.WriteString("\n" + indent)
 		}
-		records = append(records, rr)
-	}
+		elem := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+		if color {
+			elem = highlightJSON(elem)
+		}
+		n++
 
-	tw := tabwriter.NewWriter(w, 8, 8, 2, ' ', 0)
-	header = strings.TrimSpace(header) + "\n"
-	_, err := fmt.Fprint(tw, header)
+		// A write error (e.g. because the output is piped into head) stops
+		// the encoding of the remaining elements.
+		_, err := out.Write(elem)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	for _, record := range records {
-		for _, f := range fields {
-			_, err = fmt.Fprint(tw, record[f.Name]+"\t")
-			if err != nil {
-				return err
-			}
-		}
-		fmt.Fprint(tw, "\n")
+	switch {
+	case n == 0:
+		out.WriteString("[]\n")
+	case indent == "":
+		out.WriteString("]\n")
+	default:
+		out.WriteString("\n]\n")
 	}
-
-	return tw.Flush()
+	return out.Flush()
 }
 
-func stringMap(m map[string]string) string {
-	buf := new(bytes.Buffer)
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
+func printYAML(i interface{}, w io.Writer, opts *options) error {
+	out, err := yaml.Marshal(i)
+	if err != nil {
+		return err
 	}
-	sort.Strings(keys)
 
-	for i, k := range keys{
-		if i != 0 {
-			buf.WriteString(" ")
-		}
-		fmt.Fprintf(buf, "%s:%s", k, m[k])
+	if opts.useColor(w) {
+		out = highlightYAML(out)
 	}
 
-	return buf.String()
+	_, err = fmt.Fprintln(w, string(out))
+	return err
 }
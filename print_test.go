@@ -3,7 +3,7 @@ package cli
 import (
 	"bytes"
 	"encoding/json"
-	"strings"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,6 +33,82 @@ func TestPrintJSON(t *testing.T) {
 	assert.Equal(t, foo, bar)
 }
 
+func TestPrintJSON_Options(t *testing.T) {
+	value := map[string]interface{}{"html": "<b>", "list": []int{1}}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("json", value, out, JSONIndent("")))
+	assert.Equal(t, `{"html":"<b>","list":[1]}`+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("json", value, out, JSONIndent("\t"), JSONEscapeHTML(true)))
+	assert.Equal(t, "{\n\t\"html\": \"\\u003cb\\u003e\",\n\t\"list\": [\n\t\t1\n\t]\n}\n", out.String())
+}
+
+func TestPrintJSON_Array(t *testing.T) {
+	type item struct {
+		Name string            `json:"name"`
+		Tags []string          `json:"tags"`
+		Meta map[string]string `json:"meta,omitempty"`
+	}
+
+	items := []item{
+		{Name: "<a>", Tags: []string{"x", "y"}},
+		{Name: "b", Meta: map[string]string{"k": "v"}},
+	}
+
+	cases := map[string]struct {
+		value interface{}
+		opts  []Option
+	}{
+		"slice":       {value: items},
+		"array":       {value: [2]int{1, 2}},
+		"empty":       {value: []item{}},
+		"nil":         {value: []item(nil)},
+		"bytes":       {value: []byte("hello")},
+		"compact":     {value: items, opts: []Option{JSONIndent("")}},
+		"escape html": {value: items, opts: []Option{JSONEscapeHTML(true), JSONIndent("\t")}},
+		"nested":      {value: [][]int{{1}, {2, 3}, {}}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := newOptions(c.opts)
+			expected := new(bytes.Buffer)
+			enc := json.NewEncoder(expected)
+			enc.SetIndent("", o.jsonIndent)
+			enc.SetEscapeHTML(o.escapeHTML())
+			require.NoError(t, enc.Encode(c.value))
+
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("json", c.value, out, c.opts...))
+			assert.Equal(t, expected.String(), out.String())
+		})
+	}
+}
+
+func TestPrintJSON_Stream(t *testing.T) {
+	c := make(chan credentials, 2)
+	c <- credentials{User: "alice", Token: "secret"}
+	c <- credentials{User: "bob"}
+	close(c)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("json", c, out, JSONIndent("  ")))
+	expected := `[
+  {
+    "user": "alice",
+    "token": "****"
+  },
+  {
+    "user": "bob",
+    "token": ""
+  }
+]
+`
+	assert.Equal(t, expected, out.String())
+}
+
 func TestPrintYAML(t *testing.T) {
 	type someType struct {
 		Name string
@@ -55,96 +131,74 @@ func TestPrintYAML(t *testing.T) {
 	assert.Equal(t, foo, bar)
 }
 
-func TestPrintTable(t *testing.T) {
-	cases := map[string]struct {
-		instance interface{}
-		expected []string
-	}{
-		"no tags": {
-			instance: struct {
-				Name  string
-				Age   int
-				Value bool
-			}{
-				Name:  "Test",
-				Age:   42,
-				Value: true,
-			},
-			expected: []string{
-				"NAME    AGE     VALUE",
-				"Test    42      true    ",
-			},
-		},
-		"with ignore tags": {
-			instance: struct {
-				Name  string
-				Age   int
-				Value bool `table:"-"`
-			}{
-				Name:  "Test",
-				Age:   42,
-				Value: true,
-			},
-			expected: []string{
-				"NAME    AGE",
-				"Test    42      ",
-			},
-		},
-		"rename columns": {
-			instance: struct {
-				Name  string `table:"key"`
-				Age   int    `table:"age"`
-				Value bool   `table:"-"`
-			}{
-				Name:  "Test",
-				Age:   42,
-				Value: true,
-			},
-			expected: []string{
-				"key     age",
-				"Test    42      ",
-			},
-		},
-		"slice": {
-			instance: []struct {
-				Name  string
-				Age   int
-				Value bool
-			}{
-				{Name: "Foo", Age: 1, Value: true},
-				{Name: "Bar", Age: 2, Value: false},
-				{Name: "Baz", Age: 3, Value: false},
-			},
-			expected: []string{
-				"NAME    AGE     VALUE",
-				"Foo     1       true    ",
-				"Bar     2       false   ",
-				"Baz     3       false   ",
-			},
-		},
-		"slice of strings": {
-			instance: []string{"A", "B", "C"},
-			expected: []string{
-				"A",
-				"B",
-				"C",
-			},
-		},
-		"slice of ints": {
-			instance: []int{1, 2, 3},
-			expected: []string{
-				"1",
-				"2",
-				"3",
-			},
-		},
-	}
+func TestPrintErr(t *testing.T) {
+	out := new(bytes.Buffer)
+	stderr = out
+	defer func() { stderr = os.Stderr }()
 
-	for name, c := range cases {
-		t.Run(name, func(t *testing.T) {
-			out := new(bytes.Buffer)
-			require.NoError(t, PrintWriter("table", c.instance, out))
-			assert.Equal(t, strings.Join(c.expected, "\n")+"\n", out.String())
-		})
-	}
+	require.NoError(t, PrintErr("json", map[string]int{"errors": 1}))
+	assert.JSONEq(t, `{"errors": 1}`, out.String())
+
+	out.Reset()
+	Eprintf("%d files skipped", 2)
+	Eprintf("done\n")
+	Eprintln("warning:", "low disk space")
+	assert.Equal(t, "2 files skipped\ndone\nwarning: low disk space\n", out.String())
+}
+
+func TestMustPrintWriter(t *testing.T) {
+	out := new(bytes.Buffer)
+	MustPrintWriter("raw", "hello", out)
+	assert.Equal(t, "hello\n", out.String())
+
+	assert.PanicsWithError(t, `unknown encoding "xml"`, func() {
+		MustPrintWriter("xml", "hello", out)
+	})
+}
+
+func TestPrintWriter_EncodingEnv(t *testing.T) {
+	defer func(name string) { EncodingEnv = name }(EncodingEnv)
+	EncodingEnv = "CLI_TEST_OUTPUT"
+	defer os.Unsetenv(EncodingEnv)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("", "hello", out))
+	assert.Equal(t, "\"hello\"\n", out.String(), "defaults to PipedEncoding")
+
+	require.NoError(t, os.Setenv(EncodingEnv, "JSON"))
+	out.Reset()
+	require.NoError(t, PrintWriter("", "hello", out))
+	assert.Equal(t, "\"hello\"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("raw", "hello", out))
+	assert.Equal(t, "hello\n", out.String(), "explicit encoding has precedence")
+
+	require.NoError(t, os.Setenv(EncodingEnv, "raw"))
+	out.Reset()
+	require.NoError(t, PrintWriter("", "hello", out))
+	assert.Equal(t, "hello\n", out.String())
+
+	EncodingEnv = ""
+	out.Reset()
+	require.NoError(t, PrintWriter("", "hello", out))
+	assert.Equal(t, "\"hello\"\n", out.String(), "disabled")
+}
+
+func TestPrintWriter_PipedEncoding(t *testing.T) {
+	defer func(encoding string) { PipedEncoding = encoding }(PipedEncoding)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("", []string{"a", "b"}, out))
+	assert.JSONEq(t, `["a", "b"]`, out.String())
+
+	PipedEncoding = "YAML"
+	out.Reset()
+	require.NoError(t, PrintWriter("", []string{"a", "b"}, out))
+	assert.Equal(t, "- a\n- b\n\n", out.String())
+
+	PipedEncoding = ""
+	out.Reset()
+	require.NoError(t, PrintWriter("", []string{"a", "b"}, out))
+	assert.Equal(t, "a\nb\n", out.String())
 }
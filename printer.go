@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// An Encoder writes the encoding of a value to a writer. Encoders can be
+// registered on a Printer to support additional encodings.
+type Encoder interface {
+	Encode(w io.Writer, value interface{}) error
+}
+
+// The EncoderFunc type is an adapter to allow the use of ordinary functions as
+// Encoder.
+type EncoderFunc func(w io.Writer, value interface{}) error
+
+// Encode calls f(w, value).
+func (f EncoderFunc) Encode(w io.Writer, value interface{}) error {
+	return f(w, value)
+}
+
+// A PreEncodeHook is called by a Printer before a value is printed with the
+// given encoding. It returns the value that is printed instead (e.g. a copy in
+// which secrets are redacted). If it returns an error, the value is not
+// printed and the error is returned by the Printer.
+type PreEncodeHook func(encoding string, value interface{}) (interface{}, error)
+
+// A PostEncodeHook is called by a Printer after a value has been printed with
+// the given encoding. The value is the one that was printed and err is the
+// error that occurred while printing it, if any. Post-encode hooks can be used
+// to record metrics about the printed values.
+type PostEncodeHook func(encoding string, value interface{}, err error)
+
+// A Printer prints values with a preconfigured writer, encoding and options.
+// Applications usually create a single Printer at startup (e.g. after parsing
+// the command line flags) and pass it to all commands. Tests can inject a
+// Printer which writes to a buffer.
+//
+// A Printer is safe for concurrent use by multiple goroutines. Values are
+// printed one after another so the output of concurrent calls is never
+// interleaved. Note that a stream blocks all other calls until its channel is
+// closed. The exported fields must not be modified while the Printer is in
+// use.
+//
+// The zero value is a valid Printer which prints to the standard output using
+// the default encoding (see Print). A Printer must not be copied after first
+// use.
+type Printer struct {
+	// Writer is the writer that all values are printed to. If it is nil,
+	// values are printed to the standard output.
+	Writer io.Writer
+
+	// Encoding is the encoding that is used by Print. See the Print function
+	// for all supported encodings.
+	Encoding string
+
+	// Color controls whether the output is styled. It can be overridden by
+	// passing the Color option.
+	Color ColorMode
+
+	// Options are passed to each call of Print before the options of the call
+	// itself so they can be overridden.
+	Options []Option
+
+	// PreEncode hooks are called in order before each value is printed.
+	// Each hook receives the value that was returned by the previous hook.
+	PreEncode []PreEncodeHook
+
+	// PostEncode hooks are called in order after each value is printed.
+	PostEncode []PostEncodeHook
+
+	// mu serializes the output of the printer and protects the encoders.
+	mu       sync.Mutex
+	encoders map[string]Encoder
+}
+
+// NewPrinter returns a Printer which prints values to w using the given
+// encoding and options.
+func NewPrinter(w io.Writer, encoding string, opts ...Option) *Printer {
+	return &Printer{
+		Writer:   w,
+		Encoding: encoding,
+		Options:  opts,
+	}
+}
+
+// RegisterEncoder registers an encoder for the given encoding name. Encoding
+// names are case insensitive. Registered encoders take precedence over the
+// built-in encodings.
+func (p *Printer) RegisterEncoder(encoding string, enc Encoder) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.encoders == nil {
+		p.encoders = map[string]Encoder{}
+	}
+	p.encoders[strings.ToLower(encoding)] = enc
+}
+
+// Print prints the value using the encoding of the printer.
+func (p *Printer) Print(value interface{}, opts ...Option) error {
+	return p.PrintAs(p.Encoding, value, opts...)
+}
+
+// PrintAs prints the value using the given encoding instead of the encoding of
+// the printer.
+func (p *Printer) PrintAs(encoding string, value interface{}, opts ...Option) error {
+	for _, hook := range p.PreEncode {
+		var err error
+		value, err = hook(encoding, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := p.encode(encoding, value, opts)
+	for _, hook := range p.PostEncode {
+		hook(encoding, value, err)
+	}
+	return err
+}
+
+// encode prints the value using the given encoding. Concurrent calls are
+// serialized.
+func (p *Printer) encode(encoding string, value interface{}, opts []Option) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w := p.writer()
+	if enc, ok := p.encoders[strings.ToLower(encoding)]; ok {
+		return enc.Encode(w, value)
+	}
+
+	all := make([]Option, 0, len(p.Options)+len(opts)+1)
+	all = append(all, Color(p.Color))
+	all = append(all, p.Options...)
+	all = append(all, opts...)
+	return PrintWriter(encoding, value, w, all...)
+}
+
+// MustPrint is exactly like Print but panics if an error occurs.
+func (p *Printer) MustPrint(value interface{}, opts ...Option) {
+	if err := p.Print(value, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// writer returns the writer of the printer or the standard output.
+func (p *Printer) writer() io.Writer {
+	if p.Writer == nil {
+		return os.Stdout
+	}
+	return p.Writer
+}
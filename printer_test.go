@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrinter(t *testing.T) {
+	type row struct {
+		Name string
+		Age  int
+	}
+
+	out := new(bytes.Buffer)
+	p := NewPrinter(out, "table", Columns("name"), Color(ColorNever))
+
+	require.NoError(t, p.Print([]row{{"Foo", 1}}))
+	assert.Equal(t, "NAME\nFoo\n", out.String())
+
+	// Options of the call override the options of the printer.
+	out.Reset()
+	require.NoError(t, p.Print(row{"Foo", 1}, Columns("age")))
+	assert.Equal(t, "AGE\n1\n", out.String())
+
+	out.Reset()
+	require.NoError(t, p.PrintAs("json", row{"Foo", 1}))
+	assert.JSONEq(t, `{"Name": "Foo", "Age": 1}`, out.String())
+
+	assert.EqualError(t, p.PrintAs("xml", row{}), `unknown encoding "xml"`)
+}
+
+func TestPrinter_Color(t *testing.T) {
+	out := new(bytes.Buffer)
+	p := &Printer{Writer: out, Encoding: "table", Color: ColorAlways, Options: []Option{HeaderStyle(Bold)}}
+
+	require.NoError(t, p.Print([]struct{ Name string }{{"Foo"}}))
+	assert.Equal(t, "\x1b[1mNAME\x1b[0m\nFoo\n", out.String())
+}
+
+func TestPrinter_RegisterEncoder(t *testing.T) {
+	out := new(bytes.Buffer)
+	p := NewPrinter(out, "Upper")
+	p.RegisterEncoder("upper", EncoderFunc(func(w io.Writer, value interface{}) error {
+		_, err := fmt.Fprintf(w, "%s!\n", value)
+		return err
+	}))
+
+	require.NoError(t, p.Print("hello"))
+	assert.Equal(t, "hello!\n", out.String())
+}
+
+func TestPrinter_MustPrint(t *testing.T) {
+	p := NewPrinter(new(bytes.Buffer), "xml")
+	assert.PanicsWithError(t, `unknown encoding "xml"`, func() {
+		p.MustPrint("hello")
+	})
+}
+
+func TestPrinter_Hooks(t *testing.T) {
+	out := new(bytes.Buffer)
+	p := NewPrinter(out, "raw")
+
+	var printed []string
+	p.PreEncode = append(p.PreEncode,
+		func(encoding string, value interface{}) (interface{}, error) {
+			return fmt.Sprintf("<%v>", value), nil
+		},
+		func(encoding string, value interface{}) (interface{}, error) {
+			if value == "<secret>" {
+				return nil, errors.New("refusing to print secret")
+			}
+			return fmt.Sprintf("%s:%v", encoding, value), nil
+		},
+	)
+	p.PostEncode = append(p.PostEncode, func(encoding string, value interface{}, err error) {
+		printed = append(printed, fmt.Sprintf("%s %v %v", encoding, value, err))
+	})
+
+	require.NoError(t, p.Print("hello"))
+	assert.Equal(t, "raw:<hello>\n", out.String())
+
+	assert.EqualError(t, p.Print("secret"), "refusing to print secret")
+	assert.EqualError(t, p.PrintAs("xml", "world"), `unknown encoding "xml"`)
+	assert.Equal(t, []string{
+		"raw raw:<hello> <nil>",
+		`xml xml:<world> unknown encoding "xml"`,
+	}, printed)
+}
+
+// byteWriter writes each byte separately to provoke interleaved output.
+type byteWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *byteWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		w.mu.Lock()
+		w.buf.WriteByte(b)
+		w.mu.Unlock()
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+func TestPrinter_Concurrent(t *testing.T) {
+	out := new(byteWriter)
+	p := NewPrinter(out, "json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, p.Print(map[string]int{"task": i, "result": i * i}))
+		}(i)
+	}
+	wg.Wait()
+
+	dec := json.NewDecoder(&out.buf)
+	seen := map[int]bool{}
+	for dec.More() {
+		var v map[string]int
+		require.NoError(t, dec.Decode(&v))
+		assert.Equal(t, v["task"]*v["task"], v["result"])
+		seen[v["task"]] = true
+	}
+	assert.Len(t, seen, 20)
+}
@@ -0,0 +1,361 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrTooManyAttempts is returned by prompts that validate their answer if the
+// user did not give a valid answer within the number of attempts that were
+// allowed by the MaxAttempts option. The returned error wraps
+// ErrTooManyAttempts and contains the last validation error.
+var ErrTooManyAttempts = errors.New("too many invalid answers")
+
+// A PromptOption customizes an interactive prompt such as Confirm. Each option
+// documents the prompts it applies to.
+type PromptOption func(*promptOptions)
+
+type promptOptions struct {
+	defaultYes    bool
+	requireAnswer bool
+	maxAttempts   int
+	retryDelay    time.Duration
+
+	passwordPolicy func(string) error
+	comment        string
+
+	// def is the default answer. It is only used if hasDefault is true.
+	def        string
+	defValue   interface{}
+	hasDefault bool
+}
+
+func newPromptOptions(opts []PromptOption) *promptOptions {
+	o := new(promptOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// DefaultYes makes yes the answer that is used if the user just presses enter.
+// The suffix of the question is "[Y/n]" instead of "[y/N]".
+//
+// This option only has an effect on Confirm.
+func DefaultYes() PromptOption {
+	return func(o *promptOptions) {
+		o.defaultYes = true
+	}
+}
+
+// RequireAnswer asks the question again if the user just presses enter
+// instead of using the default answer. The suffix of the question is "[y/n]".
+//
+// This option only has an effect on Confirm.
+func RequireAnswer() PromptOption {
+	return func(o *promptOptions) {
+		o.requireAnswer = true
+	}
+}
+
+// Default sets the answer that is used if the user just presses enter. The
+// default is formatted like fmt.Sprint does and shown in brackets at the end
+// of the prompt (see AskDefault).
+//
+// This option only has an effect on prompts that validate their answer such
+// as AskValidated and AskInt.
+func Default(value interface{}) PromptOption {
+	return func(o *promptOptions) {
+		o.def = fmt.Sprint(value)
+		o.defValue = value
+		o.hasDefault = true
+	}
+}
+
+// PasswordPolicy sets a function that checks a new password, for example its
+// strength. If it returns an error, the error is printed and the user is
+// asked for another password.
+//
+// This option only has an effect on ReadNewPassword.
+func PasswordPolicy(fn func(password string) error) PromptOption {
+	return func(o *promptOptions) {
+		o.passwordPolicy = fn
+	}
+}
+
+// EditComment sets a text that is written as comment to the top of the file
+// that is opened in the editor, for example to explain the edited value.
+//
+// This option only has an effect on EditStruct.
+func EditComment(text string) PromptOption {
+	return func(o *promptOptions) {
+		o.comment = text
+	}
+}
+
+// MaxAttempts limits the number of answers a prompt accepts before it gives up
+// and returns an error that wraps ErrTooManyAttempts. By default the prompt is
+// written again until the answer is valid. Use this option so that automation
+// which accidentally reaches an interactive prompt fails instead of looping
+// forever.
+//
+// This option only has an effect on prompts that validate their answer such
+// as AskValidated and ReadNewPassword as well as EditStruct.
+func MaxAttempts(n int) PromptOption {
+	return func(o *promptOptions) {
+		o.maxAttempts = n
+	}
+}
+
+// RetryDelay waits for the given duration after an invalid answer before the
+// prompt is written again.
+//
+// This option only has an effect on prompts that validate their answer such
+// as AskValidated and ReadNewPassword.
+func RetryDelay(d time.Duration) PromptOption {
+	return func(o *promptOptions) {
+		o.retryDelay = d
+	}
+}
+
+// Confirm asks a yes/no question and returns true if the user answered with
+// "y" or "yes". The question is written to stderr followed by a "[y/N]"
+// suffix. Answers are case insensitive and an empty answer means no unless
+// the DefaultYes or RequireAnswer options are used. If the answer is neither
+// yes nor no, the question is asked again.
+//
+// Confirm returns false if the context is canceled or stdin was closed before
+// the user answered the question, regardless of the default answer.
+func Confirm(ctx context.Context, question string, opts ...PromptOption) bool {
+	o := newPromptOptions(opts)
+
+	suffix := "[y/N]"
+	switch {
+	case o.requireAnswer:
+		suffix = "[y/n]"
+	case o.defaultYes:
+		suffix = "[Y/n]"
+	}
+
+	for {
+		answer, err := ask(ctx, question+" "+suffix+" ")
+		if err != nil {
+			return false
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		case "":
+			if !o.requireAnswer {
+				return o.defaultYes
+			}
+		}
+	}
+}
+
+// Ask writes the prompt to stderr and reads a single line from stdin like
+// ReadLine does. The prompt is not written to stdout so the output of the
+// application can still be piped to other commands. The empty string is
+// returned if the context is canceled or stdin was closed.
+func Ask(ctx context.Context, prompt string) string {
+	answer, _ := ask(ctx, prompt)
+	return answer
+}
+
+// AskDefault asks for a value like Ask does and returns def if the user just
+// presses enter. The default is shown in brackets at the end of the prompt,
+// so a prompt of "Region: " is written as "Region [eu-west-1]: ". Like Ask,
+// it returns the empty string if the context is canceled or stdin was closed.
+func AskDefault(ctx context.Context, prompt, def string) string {
+	answer, err := ask(ctx, withDefault(prompt, def))
+	if err == nil && strings.TrimSpace(answer) == "" {
+		return def
+	}
+	return answer
+}
+
+// AskValidated asks for a value like Ask does and passes the answer to
+// validate. If validate returns an error, the error is printed like Errorf
+// does (even if Quiet is true) and the prompt is written again until the
+// answer is valid. An error is only returned if the context is canceled
+// (ctx.Err()) or stdin was closed (io.EOF) before a valid answer was read or
+// if the number of attempts that is set via the MaxAttempts option was
+// exceeded.
+func AskValidated(ctx context.Context, prompt string, validate func(string) error, opts ...PromptOption) (string, error) {
+	o := newPromptOptions(opts)
+	if o.hasDefault {
+		prompt = withDefault(prompt, o.def)
+	}
+
+	for attempt := 1; ; attempt++ {
+		answer, err := ask(ctx, prompt)
+		if err != nil {
+			return "", err
+		}
+		if o.hasDefault && strings.TrimSpace(answer) == "" {
+			answer = o.def
+		}
+
+		err = validate(answer)
+		if err == nil {
+			return answer, nil
+		}
+
+		if err := o.retry(ctx, attempt, err); err != nil {
+			return "", err
+		}
+	}
+}
+
+// retry prints the error of an invalid answer and waits for the RetryDelay
+// before the prompt is written again. It returns an error if the prompt
+// should not be written again because the number of attempts that is set via
+// MaxAttempts was reached or the context was canceled. The error is printed
+// like Errorf does but also if Quiet is true, since the user has to know why
+// the prompt is written again.
+func (o *promptOptions) retry(ctx context.Context, attempt int, err error) error {
+	writeMessage(errorPrefix, "%v", err)
+	if o.maxAttempts > 0 && attempt >= o.maxAttempts {
+		return fmt.Errorf("%w: %v", ErrTooManyAttempts, err)
+	}
+
+	if o.retryDelay > 0 {
+		select {
+		case <-time.After(o.retryDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// AskInt asks for an integer like AskValidated does. The prompt is written
+// again until the answer is a valid integer.
+func AskInt(ctx context.Context, prompt string, opts ...PromptOption) (int, error) {
+	return askAs(ctx, prompt, func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid integer", s)
+		}
+		return n, nil
+	}, opts)
+}
+
+// AskFloat asks for a floating point number like AskValidated does. The
+// prompt is written again until the answer is a valid number.
+func AskFloat(ctx context.Context, prompt string, opts ...PromptOption) (float64, error) {
+	return askAs(ctx, prompt, func(s string) (float64, error) {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid number", s)
+		}
+		return f, nil
+	}, opts)
+}
+
+// AskBool asks for a boolean like AskValidated does. Valid answers are
+// "y", "yes", "n" and "no" as well as all values that are accepted by
+// strconv.ParseBool. Answers are case insensitive. Unlike Confirm, there is
+// no default answer unless the Default option is used.
+func AskBool(ctx context.Context, prompt string, opts ...PromptOption) (bool, error) {
+	return askAs(ctx, prompt, func(s string) (bool, error) {
+		switch strings.ToLower(s) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return false, fmt.Errorf("%q is not a valid answer, please answer yes or no", s)
+		}
+		return b, nil
+	}, opts)
+}
+
+// AskDuration asks for a duration such as "30s" or "2h" like AskValidated
+// does. The answer is parsed with time.ParseDuration.
+func AskDuration(ctx context.Context, prompt string, opts ...PromptOption) (time.Duration, error) {
+	return askAs(ctx, prompt, func(s string) (time.Duration, error) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid duration (e.g. 30s or 2h)", s)
+		}
+		return d, nil
+	}, opts)
+}
+
+// AskTime asks for a time in the given layout (see time.Parse) like
+// AskValidated does. Times without a time zone are parsed in the local time
+// zone. A time.Time that is passed to the Default option is formatted using
+// the same layout.
+func AskTime(ctx context.Context, prompt, layout string, opts ...PromptOption) (time.Time, error) {
+	if t, ok := newPromptOptions(opts).defValue.(time.Time); ok {
+		opts = append(opts, Default(t.Format(layout)))
+	}
+
+	return askAs(ctx, prompt, func(s string) (time.Time, error) {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not a valid time (expected format %s)", s, layout)
+		}
+		return t, nil
+	}, opts)
+}
+
+// askAs asks for a value like AskValidated does and converts the answer
+// using the given parse function. Leading and trailing whitespace of the
+// answer is removed before it is parsed.
+func askAs[T any](ctx context.Context, prompt string, parse func(string) (T, error), opts []PromptOption) (T, error) {
+	var value T
+	_, err := AskValidated(ctx, prompt, func(answer string) error {
+		var err error
+		value, err = parse(strings.TrimSpace(answer))
+		return err
+	}, opts...)
+	return value, err
+}
+
+// withDefault returns the prompt with the given default value in brackets.
+func withDefault(prompt, def string) string {
+	if def == "" {
+		return prompt
+	}
+	return fmt.Sprintf("%s [%s]: ", strings.TrimRight(prompt, ": "), def)
+}
+
+// ask writes the prompt to stderr and reads the answer like ReadLineErr does.
+// If no answer could be read, a newline is written so any following output
+// does not end up on the line of the prompt.
+//
+// If stdin and stderr are terminals, the answer can be edited with the arrow
+// keys and previous answers can be recalled from the history (see
+// SetHistoryFile).
+func ask(ctx context.Context, prompt string) (string, error) {
+	var answer string
+	ok, err := withRawStdin(func() (err error) {
+		answer, err = editLine(ctx, stdinLineReader(), stderr, prompt, historyEntries())
+		return err
+	})
+	if !ok && err == nil {
+		fmt.Fprint(stderr, prompt)
+		answer, err = ReadLineErr(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr)
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+	addHistory(answer)
+	return answer, nil
+}
@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPrompt replaces stdin with the given input and returns a buffer that
+// receives everything that is written to stderr.
+func mockPrompt(t *testing.T, input string) *bytes.Buffer {
+	t.Cleanup(func() { stdin, stderr = os.Stdin, os.Stderr })
+	stdin = strings.NewReader(input)
+	out := new(bytes.Buffer)
+	stderr = out
+	return out
+}
+
+func TestConfirm(t *testing.T) {
+	ctx := context.Background()
+	for input, expected := range map[string]bool{
+		"y\n":        true,
+		"YES\n":      true,
+		" Yes \r\n":  true,
+		"n\n":        false,
+		"No\n":       false,
+		"\n":         false,
+		"":           false,
+		"maybe\ny\n": true,
+	} {
+		t.Run(input, func(t *testing.T) {
+			mockPrompt(t, input)
+			assert.Equal(t, expected, Confirm(ctx, "Delete?"))
+		})
+	}
+
+	out := mockPrompt(t, "what?\nno\n")
+	assert.False(t, Confirm(ctx, "Delete everything?"))
+	assert.Equal(t, "Delete everything? [y/N] Delete everything? [y/N] ", out.String())
+}
+
+func TestConfirm_DefaultYes(t *testing.T) {
+	out := mockPrompt(t, "\nn\n")
+	ctx := context.Background()
+
+	assert.True(t, Confirm(ctx, "Continue?", DefaultYes()))
+	assert.False(t, Confirm(ctx, "Continue?", DefaultYes()))
+	assert.Equal(t, "Continue? [Y/n] Continue? [Y/n] ", out.String())
+}
+
+func TestConfirm_RequireAnswer(t *testing.T) {
+	out := mockPrompt(t, "\n\ny\n")
+
+	assert.True(t, Confirm(context.Background(), "Continue?", RequireAnswer()))
+	assert.Equal(t, strings.Repeat("Continue? [y/n] ", 3), out.String())
+}
+
+func TestConfirm_Canceled(t *testing.T) {
+	mockPrompt(t, "y\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.False(t, Confirm(ctx, "Delete?"))
+	assert.False(t, Confirm(ctx, "Delete?", DefaultYes()))
+}
+
+func TestAsk(t *testing.T) {
+	out := mockPrompt(t, "Alice\n")
+	ctx := context.Background()
+
+	assert.Equal(t, "Alice", Ask(ctx, "Name: "))
+	assert.Equal(t, "", Ask(ctx, "Age: "))
+	assert.Equal(t, "Name: Age: \n", out.String())
+}
+
+func TestAskDefault(t *testing.T) {
+	out := mockPrompt(t, "\nus-east-1\n")
+	ctx := context.Background()
+
+	assert.Equal(t, "eu-west-1", AskDefault(ctx, "Region: ", "eu-west-1"))
+	assert.Equal(t, "us-east-1", AskDefault(ctx, "Region", "eu-west-1"))
+	assert.Equal(t, "", AskDefault(ctx, "Zone: ", ""))
+	assert.Equal(t, "Region [eu-west-1]: Region [eu-west-1]: Zone: \n", out.String())
+}
+
+func TestAskValidated(t *testing.T) {
+	out := mockPrompt(t, "abc\n42\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+
+	validate := func(s string) error {
+		if _, err := strconv.Atoi(s); err != nil {
+			return errors.New("not a number")
+		}
+		return nil
+	}
+
+	answer, err := AskValidated(context.Background(), "Port: ", validate)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", answer)
+	assert.Equal(t, "Port: ✗ not a number\nPort: ", out.String())
+
+	_, err = AskValidated(context.Background(), "Port: ", validate)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestAskValidated_MaxAttempts(t *testing.T) {
+	out := mockPrompt(t, "a\nb\nc\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+
+	validate := func(s string) error {
+		return fmt.Errorf("invalid answer %q", s)
+	}
+
+	_, err := AskValidated(context.Background(), "? ", validate, MaxAttempts(2), RetryDelay(time.Millisecond))
+	assert.True(t, errors.Is(err, ErrTooManyAttempts))
+	assert.EqualError(t, err, `too many invalid answers: invalid answer "b"`)
+	assert.Equal(t, "? ✗ invalid answer \"a\"\n? ✗ invalid answer \"b\"\n", out.String())
+}
+
+func TestAskValidated_Quiet(t *testing.T) {
+	out := mockPrompt(t, "abc\n42\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+	defer func(q bool) { Quiet = q }(Quiet)
+	Quiet = true
+
+	n, err := AskInt(context.Background(), "Port: ")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, n)
+	assert.Equal(t, "Port: ✗ \"abc\" is not a valid integer\nPort: ", out.String())
+}
+
+func TestAskValidated_RetryDelayCanceled(t *testing.T) {
+	mockPrompt(t, "a\nb\n")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	validate := func(string) error { return errors.New("invalid") }
+	_, err := AskValidated(ctx, "? ", validate, RetryDelay(time.Hour))
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestAskValidated_Default(t *testing.T) {
+	out := mockPrompt(t, "\n")
+
+	answer, err := AskValidated(context.Background(), "Name: ", func(string) error { return nil }, Default("alice"))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", answer)
+	assert.Equal(t, "Name [alice]: ", out.String())
+}
+
+func TestAskInt(t *testing.T) {
+	out := mockPrompt(t, "eight\n 8 \n\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+	ctx := context.Background()
+
+	n, err := AskInt(ctx, "Workers: ")
+	assert.NoError(t, err)
+	assert.Equal(t, 8, n)
+	assert.Equal(t, "Workers: ✗ \"eight\" is not a valid integer\nWorkers: ", out.String())
+
+	n, err = AskInt(ctx, "Workers: ", Default(4))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+}
+
+func TestAskFloat(t *testing.T) {
+	mockPrompt(t, "0.5\n")
+
+	f, err := AskFloat(context.Background(), "Ratio: ")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, f)
+}
+
+func TestAskBool(t *testing.T) {
+	mockPrompt(t, "maybe\nYes\nfalse\n\n")
+	ctx := context.Background()
+
+	b, err := AskBool(ctx, "Enabled? ")
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = AskBool(ctx, "Enabled? ")
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	b, err = AskBool(ctx, "Enabled? ", Default(true))
+	assert.NoError(t, err)
+	assert.True(t, b)
+}
+
+func TestAskDuration(t *testing.T) {
+	out := mockPrompt(t, "soon\n2h\n\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+	ctx := context.Background()
+
+	d, err := AskDuration(ctx, "Timeout: ")
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, d)
+	assert.Equal(t, "Timeout: ✗ \"soon\" is not a valid duration (e.g. 30s or 2h)\nTimeout: ", out.String())
+
+	d, err = AskDuration(ctx, "Timeout: ", Default(30*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, d)
+}
+
+func TestAskTime(t *testing.T) {
+	out := mockPrompt(t, "tomorrow\n2021-03-04\n\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+	ctx := context.Background()
+
+	ts, err := AskTime(ctx, "Date: ", "2006-01-02")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 4, 0, 0, 0, 0, time.Local), ts)
+	assert.Equal(t, "Date: ✗ \"tomorrow\" is not a valid time (expected format 2006-01-02)\nDate: ", out.String())
+
+	out.Reset()
+	def := time.Date(2020, 1, 2, 0, 0, 0, 0, time.Local)
+	ts, err = AskTime(ctx, "Date: ", "2006-01-02", Default(def))
+	assert.NoError(t, err)
+	assert.Equal(t, def, ts)
+	assert.Equal(t, "Date [2020-01-02]: ", out.String())
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// printIDs prints the identifier of each row of v on its own line. If v is a
+// channel, the identifiers are printed as soon as the values are received.
+// Values that are not structs are printed like the "table" encoding does. The
+// rows are sorted, grouped and limited like the rows of the table, so the same
+// rows are printed in the same order.
+func printIDs(v interface{}, w io.Writer, opts *options) error {
+	var (
+		t    reflect.Type
+		rows []reflect.Value
+		c    reflect.Value
+	)
+
+	stream := isStream(v)
+	if stream {
+		c = reflect.ValueOf(v)
+		t = baseType(c.Type().Elem())
+	} else {
+		t, rows, _ = tableRows(reflect.ValueOf(v))
+	}
+
+	if t.Kind() != reflect.Struct {
+		if stream {
+			return printTableStream(v, w, opts)
+		}
+		return printTable(v, w, opts)
+	}
+
+	fields, err := tableFields(t, opts)
+	if err != nil {
+		return err
+	}
+
+	f, ok := idField(fields)
+	if !ok {
+		return nil
+	}
+
+	printID := func(row reflect.Value) error {
+		_, err := fmt.Fprintln(w, formatCell(f.value(row), f, opts))
+		return err
+	}
+
+	if !stream {
+		rows, err = tableOrder(fields, rows, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if err := printID(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if opts.sortBy != "" || opts.groupBy != "" || opts.splitBy != "" {
+		return errors.New("cannot sort, group or split a stream")
+	}
+
+	// Like printTableStream, no more values are received once the limit
+	// has been reached.
+	for i := 0; opts.limit <= 0 || i < opts.limit; i++ {
+		row, ok := c.Recv()
+		if !ok {
+			return nil
+		}
+		if err := printID(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableOrder returns the rows in the order in which they are printed as a
+// table according to the SortBy, SplitBy, GroupBy and Limit options. Rows that
+// are omitted due to the Limit option are not returned.
+func tableOrder(fields []field, rows []reflect.Value, opts *options) ([]reflect.Value, error) {
+	if err := sortTable(fields, rows, opts); err != nil {
+		return nil, err
+	}
+
+	var group field
+	if opts.groupBy != "" {
+		var ok bool
+		group, ok = findField(fields, opts.groupBy)
+		if !ok {
+			return nil, fmt.Errorf("cannot group by unknown column %q", opts.groupBy)
+		}
+	}
+
+	// Each table of the SplitBy option is grouped and limited on its own.
+	tables := [][]reflect.Value{rows}
+	if opts.splitBy != "" {
+		split, ok := findField(fields, opts.splitBy)
+		if !ok {
+			return nil, fmt.Errorf("cannot split by unknown column %q", opts.splitBy)
+		}
+
+		tables, _ = splitRows(rows, split, opts)
+	}
+
+	var result []reflect.Value
+	for _, table := range tables {
+		if opts.groupBy != "" {
+			table, _ = groupRows(table, group, opts)
+		}
+		table, _ = limitRows(table, opts)
+		result = append(result, table...)
+	}
+	return result, nil
+}
+
+// idField returns the field that identifies the rows of a table. This is the
+// field with an "id" tag option, the field with the column name "ID" or the
+// first field.
+func idField(fields []field) (field, bool) {
+	if len(fields) == 0 {
+		return field{}, false
+	}
+
+	for _, f := range fields {
+		if f.Tag.ID {
+			return f, true
+		}
+	}
+
+	for _, f := range fields {
+		if strings.EqualFold(f.Column(), "ID") {
+			return f, true
+		}
+	}
+
+	return fields[0], true
+}
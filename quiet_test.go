@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuiet(t *testing.T) {
+	type container struct {
+		Name  string
+		ID    string
+		Image string
+	}
+
+	type volume struct {
+		Driver string
+		Name   string `table:"VOLUME NAME,id"`
+	}
+
+	type user struct {
+		Name  string
+		Email string
+	}
+
+	cases := map[string]struct {
+		encoding string
+		value    interface{}
+		expected string
+	}{
+		"id column": {
+			encoding: "table",
+			value:    []container{{Name: "web", ID: "a1", Image: "nginx"}, {Name: "db", ID: "b2", Image: "postgres"}},
+			expected: "a1\nb2\n",
+		},
+		"id option": {
+			encoding: "detail",
+			value:    []volume{{Driver: "local", Name: "data"}},
+			expected: "data\n",
+		},
+		"first column": {
+			encoding: "table",
+			value:    &user{Name: "alice", Email: "alice@example.com"},
+			expected: "alice\n",
+		},
+		"stream": {
+			encoding: "table",
+			value: func() <-chan user {
+				c := make(chan user, 2)
+				c <- user{Name: "alice"}
+				c <- user{Name: "bob"}
+				close(c)
+				return c
+			}(),
+			expected: "alice\nbob\n",
+		},
+		"scalar": {
+			encoding: "table",
+			value:    "hello",
+			expected: "hello\n",
+		},
+		"other encodings": {
+			encoding: "csv",
+			value:    []user{{Name: "alice", Email: "alice@example.com"}},
+			expected: "NAME,EMAIL\nalice,alice@example.com\n",
+		},
+	}
+
+	defer func() { Quiet = false }()
+	Quiet = true
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter(c.encoding, c.value, out))
+			assert.Equal(t, c.expected, out.String())
+		})
+	}
+
+	t.Run("messages", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		stderr = out
+		defer func() { stderr = os.Stderr }()
+
+		Successf("done")
+		Warnf("careful")
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestPrint_QuietOrder(t *testing.T) {
+	type row struct {
+		ID   string
+		Team string
+	}
+	rows := []row{{"b", "x"}, {"a", "y"}, {"c", "x"}}
+
+	defer func() { Quiet = false }()
+	Quiet = true
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, SortBy("ID", false), Limit(2)))
+	assert.Equal(t, "a\nb\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, GroupBy("team")))
+	assert.Equal(t, "b\nc\na\n", out.String())
+
+	c := make(chan row, 3)
+	for _, r := range rows {
+		c <- r
+	}
+	close(c)
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", c, out, Limit(2)))
+	assert.Equal(t, "b\na\n", out.String())
+
+	err := PrintWriter("table", c, out, SortBy("id", false))
+	assert.EqualError(t, err, "cannot sort, group or split a stream")
+}
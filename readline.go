@@ -2,81 +2,662 @@ package cli
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
 )
 
+// ErrLineTooLong is returned when a line or record is longer than the maximum
+// length of a Reader (see Reader.SetMaxLength). The line is discarded, so the
+// next read continues with the line after it.
+var ErrLineTooLong = errors.New("line too long")
+
 // stdin is the io.Reader that lines are read from.
-// This is variable so we can mock it in tests.
-// This is not injected into ReadLines for pragmatic convenience reasons.
+// This is variable so we can mock it in tests. Use ReadLineFrom or
+// ReadLinesFrom to read from any other io.Reader.
 var stdin io.Reader = os.Stdin
 
-// ReadLine reads a single line from stdin and returns it without the trailing
-// newline. This function blocks until the first newline is read or the context
-// is canceled. In the later case the empty string is returned.
-func ReadLine(ctx context.Context) string {
-	r := bufio.NewReader(stdin)
+var (
+	stdinMu     sync.Mutex
+	stdinSource io.Reader
+	stdinReader *lineReader
+)
 
-	input := make(chan string)
-	go func() {
-		line, err := r.ReadString('\n')
-		if err != nil || len(line) == 0 {
-			input <- ""
-			return
+// stdinLineReader returns the lineReader that is shared by all functions which
+// read from stdin. A new lineReader is created whenever stdin was replaced.
+func stdinLineReader() *lineReader {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+	if stdinReader == nil || stdinSource != stdin {
+		stdinSource = stdin
+		stdinReader = newLineReader(stdin)
+	}
+	return stdinReader
+}
+
+// Reader reads lines or other records from an io.Reader. All reads share the
+// same buffer, so successive calls never lose any input that was already read
+// from the underlying io.Reader, even if their context was canceled.
+type Reader struct {
+	lr *lineReader
+}
+
+// NewReader returns a new Reader which reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{lr: newLineReader(r)}
+}
+
+// SetMaxLength limits the length of the lines and records that are read by r
+// to n bytes, not counting the delimiter. Longer lines are discarded without
+// buffering them and ErrLineTooLong is returned instead. A value of zero or
+// less removes the limit, which is the default. The limit does not apply to
+// ReadAll and Read.
+//
+// Use this to protect against unbounded memory usage if the input is not
+// trusted.
+func (r *Reader) SetMaxLength(n int) {
+	r.lr.sem <- struct{}{}
+	defer r.lr.unlock()
+	r.lr.maxLength = n
+}
+
+// ReadLine reads a single line like ReadLineErr does.
+func (r *Reader) ReadLine(ctx context.Context) (string, error) {
+	return r.lr.readRecord(ctx, '\n')
+}
+
+// ReadLines reads lines like ReadLinesErr does.
+func (r *Reader) ReadLines(ctx context.Context) (<-chan string, <-chan error) {
+	return readRecordsErr(ctx, r.lr, '\n')
+}
+
+// ReadUntil reads until the first occurrence of delim like ReadUntil does.
+func (r *Reader) ReadUntil(ctx context.Context, delim byte) (string, error) {
+	return r.lr.readRecord(ctx, delim)
+}
+
+// ReadRecords reads records that are separated by delim like ReadRecords does.
+func (r *Reader) ReadRecords(ctx context.Context, delim byte) (<-chan string, <-chan error) {
+	return readRecordsErr(ctx, r.lr, delim)
+}
+
+// ReadLinesBatch reads lines in batches of up to n lines like ReadLinesBatch
+// does but reports errors like ReadLinesErr does.
+func (r *Reader) ReadLinesBatch(ctx context.Context, n int) (<-chan []string, <-chan error) {
+	checkBatchSize(n)
+	lines, errs := r.ReadLines(ctx)
+	return batchLines(lines, n), errs
+}
+
+// ReadAll reads until the end of the input like ReadAll does.
+func (r *Reader) ReadAll(ctx context.Context) ([]byte, error) {
+	return r.lr.readAll(ctx)
+}
+
+// Read implements io.Reader. Input that was already read from the underlying
+// io.Reader by ReadLine or ReadLines is returned first, so the Reader can be
+// passed to other functions without losing any input.
+func (r *Reader) Read(p []byte) (int, error) {
+	return r.lr.read(p)
+}
+
+// lineReaderFor returns the lineReader that should be used to read from r.
+func lineReaderFor(r io.Reader) *lineReader {
+	if r, ok := r.(*Reader); ok {
+		return r.lr
+	}
+	if r == stdin {
+		return stdinLineReader()
+	}
+	return newLineReader(r)
+}
+
+// lineResult is the result of reading a single record. The record includes
+// its delimiter unless it is the last record of the input.
+type lineResult struct {
+	line []byte
+	err  error
+}
+
+// lineReader reads records (usually lines) from an io.Reader in a background
+// goroutine so the read can be abandoned if a context is canceled. A read that
+// is still in progress when its context is canceled is not discarded. Instead
+// its record is returned by the next read so no input is lost.
+type lineReader struct {
+	r *bufio.Reader
+
+	// sem is held by the goroutine that currently reads from the
+	// lineReader and protects the fields below.
+	sem          chan struct{}
+	pending      chan lineResult
+	pendingDelim byte
+	pendingRaw   bool   // the pending read is not a record read (see readAll)
+	rest         []byte // input that was read but not returned yet
+	restErr      error  // error of the read that returned rest
+	maxLength    int    // maximum length of a record or 0 if unlimited
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: bufio.NewReader(r), sem: make(chan struct{}, 1)}
+}
+
+// readRecord returns the next record without the trailing delimiter. It
+// returns io.EOF if there is no more input and ctx.Err() if the context was
+// canceled before a record was read.
+func (lr *lineReader) readRecord(ctx context.Context, delim byte) (string, error) {
+	if err := lr.lock(ctx); err != nil {
+		return "", err
+	}
+	defer lr.unlock()
+
+	line, err := lr.next(ctx, delim)
+	return trimDelim(line, delim), err
+}
+
+// trimDelim removes the trailing delimiter of the given record. If the
+// delimiter is a newline, both "\n" and "\r\n" line endings are supported.
+func trimDelim(line string, delim byte) string {
+	if delim != '\n' {
+		return strings.TrimSuffix(line, string(delim))
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r")
+}
+
+// lock acquires the semaphore of the lineReader or returns ctx.Err() if the
+// context is canceled first.
+func (lr *lineReader) lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case lr.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (lr *lineReader) unlock() {
+	<-lr.sem
+}
+
+// next returns the next record including its trailing delimiter. The caller
+// must hold the semaphore.
+func (lr *lineReader) next(ctx context.Context, delim byte) (string, error) {
+	for {
+		if i := bytes.IndexByte(lr.rest, delim); i >= 0 {
+			line := lr.rest[:i+1]
+			lr.rest = lr.rest[i+1:]
+			if lr.maxLength > 0 && i > lr.maxLength {
+				return "", ErrLineTooLong
+			}
+			return string(line), nil
+		}
+
+		if err := lr.restErr; err != nil {
+			line := lr.rest
+			lr.rest, lr.restErr = nil, nil
+			if err != io.EOF || len(line) == 0 {
+				return "", err
+			}
+			if lr.maxLength > 0 && len(line) > lr.maxLength {
+				return "", ErrLineTooLong
+			}
+			return string(line), nil
+		}
+
+		if lr.pending == nil {
+			lr.startRead(delim)
 		}
-		input <- line[:len(line)-1]
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case res := <-lr.pending:
+			lr.pending = nil
+			if (lr.pendingRaw || lr.pendingDelim != delim) && len(res.line) > 0 {
+				// The pending read was started with another
+				// delimiter or by readAll, so its input may
+				// contain any number of records. Its error is
+				// returned once the input has been consumed.
+				lr.rest, lr.restErr = res.line, res.err
+				continue
+			}
+			if res.err == io.EOF && len(res.line) > 0 {
+				res.err = nil
+			}
+			if res.err != nil {
+				return "", res.err
+			}
+			return string(res.line), nil
+		}
+	}
+}
+
+// startRead starts reading the next record in the background. The input that
+// was already read is used as prefix for the next record.
+func (lr *lineReader) startRead(delim byte) {
+	prefix := lr.rest
+	lr.rest = nil
+	maxLength := lr.maxLength
+
+	c := make(chan lineResult, 1)
+	go func() {
+		line, err := readDelim(lr.r, prefix, delim, maxLength)
+		c <- lineResult{line: line, err: err}
 	}()
 
+	lr.pending = c
+	lr.pendingDelim = delim
+	lr.pendingRaw = false
+}
+
+// readAll returns all remaining input. If the context is canceled, the input
+// that was read so far is returned together with ctx.Err().
+func (lr *lineReader) readAll(ctx context.Context) ([]byte, error) {
+	if err := lr.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer lr.unlock()
+
+	data, err := lr.rest, lr.restErr
+	lr.rest, lr.restErr = nil, nil
+	switch {
+	case err == io.EOF:
+		return data, nil
+	case err != nil:
+		return data, err
+	}
+
+	for {
+		chunk, err := lr.nextChunk(ctx)
+		data = append(data, chunk...)
+		switch {
+		case err == io.EOF:
+			return data, nil
+		case err != nil:
+			return data, err
+		}
+	}
+}
+
+// readKeys passes the input of lr byte by byte to fn until fn returns true or
+// an error. It is used to read from a terminal in raw mode where each key
+// press is sent as soon as it is typed. Input after the last byte that was
+// passed to fn is kept for the next read.
+func (lr *lineReader) readKeys(ctx context.Context, fn func(key byte) (bool, error)) error {
+	if err := lr.lock(ctx); err != nil {
+		return err
+	}
+	defer lr.unlock()
+
+	for {
+		for len(lr.rest) > 0 {
+			key := lr.rest[0]
+			lr.rest = lr.rest[1:]
+			if done, err := fn(key); done || err != nil {
+				return err
+			}
+		}
+
+		if err := lr.restErr; err != nil {
+			lr.restErr = nil
+			return err
+		}
+
+		chunk, err := lr.nextChunk(ctx)
+		lr.rest = chunk
+		if err != nil && len(chunk) == 0 {
+			return err
+		}
+		lr.restErr = err
+	}
+}
+
+// nextChunk returns the next chunk of input that is read from the underlying
+// io.Reader. It does not return the input in lr.rest. The caller must hold
+// the semaphore.
+func (lr *lineReader) nextChunk(ctx context.Context) ([]byte, error) {
+	if lr.pending == nil {
+		lr.startRawRead()
+	}
+
 	select {
 	case <-ctx.Done():
-		return ""
-	case s := <-input:
-		return s
+		return nil, ctx.Err()
+	case res := <-lr.pending:
+		lr.pending = nil
+		return res.line, res.err
 	}
 }
 
-// ReadLines reads lines from stdin and returns them in a channel.
-// All strings in the returned channel will not include the trailing newline.
-// The channel is closed automatically if there are no more lines or if the
-// context is closed.
-//
-// This function panics if there was any error other than io.EOF when reading
-// from os.Stdin.
-func ReadLines(ctx context.Context) <-chan string {
-	r := bufio.NewReader(stdin)
-	c := make(chan string)
+// startRawRead starts reading the next chunk of input in the background.
+func (lr *lineReader) startRawRead() {
+	c := make(chan lineResult, 1)
+	go func() {
+		buf := make([]byte, 32*1024)
+		n, err := lr.r.Read(buf)
+		c <- lineResult{line: buf[:n], err: err}
+	}()
+
+	lr.pending = c
+	lr.pendingRaw = true
+}
+
+// readDelim reads from r until the first occurrence of delim and appends the
+// input to buf. If maxLength is positive and the record without its delimiter
+// is longer than maxLength bytes, the rest of the record is discarded and
+// ErrLineTooLong is returned.
+func readDelim(r *bufio.Reader, buf []byte, delim byte, maxLength int) ([]byte, error) {
+	if maxLength <= 0 {
+		line, err := r.ReadBytes(delim)
+		return append(buf, line...), err
+	}
+
+	for {
+		chunk, err := r.ReadSlice(delim)
+		buf = append(buf, chunk...)
+
+		n := len(buf)
+		if err == nil {
+			n-- // the delimiter does not count
+		}
+		if n > maxLength {
+			for err == bufio.ErrBufferFull {
+				_, err = r.ReadSlice(delim)
+			}
+			if err == nil || err == io.EOF {
+				err = ErrLineTooLong
+			}
+			return nil, err
+		}
+
+		if err != bufio.ErrBufferFull {
+			return buf, err
+		}
+	}
+}
+
+// read implements io.Reader. Input that was already read by a record read is
+// returned first.
+func (lr *lineReader) read(p []byte) (int, error) {
+	lr.sem <- struct{}{}
+	defer lr.unlock()
+
+	if lr.pending != nil {
+		res := <-lr.pending
+		lr.pending = nil
+		lr.rest = append(res.line, lr.rest...)
+		if res.err != nil {
+			lr.restErr = res.err
+		}
+	}
+
+	if len(lr.rest) == 0 && lr.restErr != nil {
+		err := lr.restErr
+		lr.restErr = nil
+		return 0, err
+	}
+
+	if len(lr.rest) > 0 {
+		n := copy(p, lr.rest)
+		lr.rest = lr.rest[n:]
+		return n, nil
+	}
+
+	return lr.r.Read(p)
+}
+
+// readRecords reads records until there are no more records, reading failed or
+// the context is canceled and sends them without the trailing delimiter to the
+// returned channel. Other reads from the lineReader block until the channel is
+// closed. The function done is called with the error that ended the loop (or
+// nil if the end of the input was reached) before the channel is closed.
+func (lr *lineReader) readRecords(ctx context.Context, delim byte, done func(error)) <-chan string {
+	records := make(chan string)
 	go func() {
+		defer close(records)
+		if err := lr.lock(ctx); err != nil {
+			done(err)
+			return
+		}
+		defer lr.unlock()
+
 		for {
-			line, err := r.ReadString('\n')
+			record, err := lr.next(ctx, delim)
 			switch {
 			case err == io.EOF:
-				close(c)
+				done(nil)
 				return
 			case err != nil:
-				panic(err)
+				done(err)
+				return
+			}
+
+			select {
+			case records <- trimDelim(record, delim):
+			case <-ctx.Done():
+				// The record is kept for the next read.
+				lr.rest = append([]byte(record), lr.rest...)
+				done(ctx.Err())
+				return
 			}
+		}
+	}()
+
+	return records
+}
+
+// ReadLine reads a single line from stdin and returns it without the trailing
+// newline (either "\n" or "\r\n"). This function blocks until the first
+// newline is read or the context is canceled. In the later case the empty
+// string is returned.
+//
+// Use ReadLineErr if you need to distinguish an empty line from the end of the
+// input or a canceled context.
+func ReadLine(ctx context.Context) string {
+	line, _ := ReadLineErr(ctx)
+	return line
+}
+
+// ReadLineErr reads a single line from stdin like ReadLine does but also
+// returns an error if no line could be read. The error is io.EOF if stdin was
+// closed before any input was read and ctx.Err() if the context was canceled.
+// Any other error is returned as is. If the input ends without a trailing
+// newline, the remaining input is returned as the last line without an error.
+//
+// If the context is canceled while waiting for input, the line that is entered
+// next is not lost but returned by the next function that reads from stdin.
+func ReadLineErr(ctx context.Context) (string, error) {
+	return stdinLineReader().readRecord(ctx, '\n')
+}
 
-			c <- line[:len(line)-1]
+// ReadLineFrom reads a single line from r like ReadLineErr reads from stdin.
+// Input is read from r in chunks, so any input that was read beyond the line
+// is lost unless r is a *Reader (see NewReader) or stdin.
+func ReadLineFrom(ctx context.Context, r io.Reader) (string, error) {
+	return lineReaderFor(r).readRecord(ctx, '\n')
+}
+
+// ReadLines reads lines from stdin and returns them in a channel.
+// All strings in the returned channel will not include the trailing newline.
+// The channel is closed automatically if there are no more lines or if the
+// context is closed. Other functions that read from stdin block until the
+// channel is closed. Lines that have not been received when the context is
+// closed are returned by the next function that reads from stdin.
+//
+// This function panics if there was any error other than io.EOF when reading
+// from os.Stdin. Use ReadLinesErr to handle such errors instead.
+func ReadLines(ctx context.Context) <-chan string {
+	return stdinLineReader().readRecords(ctx, '\n', func(err error) {
+		if err != nil && err != ctx.Err() {
+			panic(err)
+		}
+	})
+}
+
+// ReadLinesErr reads lines from stdin like ReadLines does but reports errors
+// on the returned error channel instead of panicking. Both channels are closed
+// if there are no more lines, if reading from stdin failed or if the context is
+// closed. In the later two cases the error channel receives the read error or
+// ctx.Err() before the lines channel is closed. The error channel is buffered,
+// so it can be read after all lines have been received:
+//
+//	lines, errs := cli.ReadLinesErr(ctx)
+//	for line := range lines {
+//		// …
+//	}
+//	if err := <-errs; err != nil {
+//		// …
+//	}
+func ReadLinesErr(ctx context.Context) (<-chan string, <-chan error) {
+	return readRecordsErr(ctx, stdinLineReader(), '\n')
+}
+
+// ReadLinesFrom reads lines from r like ReadLinesErr reads from stdin. Like
+// with ReadLineFrom, input that was not received is lost unless r is a *Reader
+// or stdin.
+func ReadLinesFrom(ctx context.Context, r io.Reader) (<-chan string, <-chan error) {
+	return readRecordsErr(ctx, lineReaderFor(r), '\n')
+}
+
+// ReadUntil reads from stdin until the first occurrence of delim and returns
+// the input without the delimiter. Errors are returned like ReadLineErr does.
+func ReadUntil(ctx context.Context, delim byte) (string, error) {
+	return stdinLineReader().readRecord(ctx, delim)
+}
+
+// ReadRecords reads records that are separated by delim from stdin like
+// ReadLinesErr reads lines. Use a NUL delimiter to read the output of commands
+// like "find -print0".
+func ReadRecords(ctx context.Context, delim byte) (<-chan string, <-chan error) {
+	return readRecordsErr(ctx, stdinLineReader(), delim)
+}
+
+// ReadLinesBatch reads lines from stdin like ReadLines does but groups them
+// into slices of up to n lines. A batch is sent as soon as it is full. The
+// last batch may contain less than n lines and is sent once there are no more
+// lines or the context is closed, so the channel should be drained until it
+// is closed. This function panics if n is less than one or if reading from
+// stdin failed like ReadLines does.
+func ReadLinesBatch(ctx context.Context, n int) <-chan []string {
+	checkBatchSize(n)
+	return batchLines(ReadLines(ctx), n)
+}
+
+func checkBatchSize(n int) {
+	if n < 1 {
+		panic("cli: batch size must be positive")
+	}
+}
+
+// batchLines groups the lines of the given channel into slices of up to n
+// lines. The returned channel is closed after the lines channel was closed
+// and the last batch was sent.
+func batchLines(lines <-chan string, n int) <-chan []string {
+	batches := make(chan []string)
+	go func() {
+		defer close(batches)
+		batch := make([]string, 0, n)
+		for line := range lines {
+			batch = append(batch, line)
+			if len(batch) == n {
+				batches <- batch
+				batch = make([]string, 0, n)
+			}
+		}
+		if len(batch) > 0 {
+			batches <- batch
 		}
 	}()
 
-	lines := make(chan string)
+	return batches
+}
+
+// ReadLinesAs reads lines from stdin like ReadLinesErr does and converts each
+// line using the given parse function. If parse returns an error, reading
+// stops and the error is reported on the error channel together with the
+// number of the line that could not be parsed. Lines that were not parsed
+// yet are returned by the next function that reads from stdin.
+//
+//	ids, errs := cli.ReadLinesAs(ctx, strconv.Atoi)
+//	for id := range ids {
+//		// …
+//	}
+//	if err := <-errs; err != nil {
+//		// …
+//	}
+func ReadLinesAs[T any](ctx context.Context, parse func(string) (T, error)) (<-chan T, <-chan error) {
+	return readLinesAs(ctx, parse, false)
+}
+
+// readLinesAs implements ReadLinesAs. If skipBlank is true, lines that only
+// contain whitespace are not passed to parse.
+func readLinesAs[T any](ctx context.Context, parse func(string) (T, error), skipBlank bool) (<-chan T, <-chan error) {
+	ctx, cancel := context.WithCancel(ctx)
+	lines, lineErrs := ReadLinesErr(ctx)
+
+	values := make(chan T)
+	errs := make(chan error, 1)
 	go func() {
-		for {
-			select {
-			case l, ok := <-c:
-				if !ok {
-					close(lines)
-					return
+		defer close(values)
+		defer cancel()
+
+		err := func() error {
+			n := 0
+			for line := range lines {
+				n++
+				if skipBlank && strings.TrimSpace(line) == "" {
+					continue
+				}
+
+				v, err := parse(line)
+				if err != nil {
+					return fmt.Errorf("line %d: %w", n, err)
+				}
+
+				select {
+				case values <- v:
+				case <-ctx.Done():
+					return ctx.Err()
 				}
-				lines <- l
-			case <-ctx.Done():
-				close(lines)
-				return
 			}
+			return <-lineErrs
+		}()
+
+		if err != nil {
+			errs <- err
 		}
+		close(errs)
 	}()
 
-	return lines
+	return values, errs
+}
+
+// ReadAll reads from stdin until the end of the input and returns everything
+// that was read. Unlike ioutil.ReadAll it returns as soon as the context is
+// canceled, together with the input that was read so far and ctx.Err().
+// Input that is read from stdin after the context was canceled is returned by
+// the next function that reads from stdin.
+func ReadAll(ctx context.Context) ([]byte, error) {
+	return stdinLineReader().readAll(ctx)
+}
+
+func readRecordsErr(ctx context.Context, lr *lineReader, delim byte) (<-chan string, <-chan error) {
+	errs := make(chan error, 1)
+	records := lr.readRecords(ctx, delim, func(err error) {
+		if err != nil {
+			errs <- err
+		}
+		close(errs)
+	})
+
+	return records, errs
 }
@@ -2,7 +2,11 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"io"
+	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -54,6 +58,62 @@ func TestReadLine_BlockContext(t *testing.T) {
 	}
 }
 
+func TestReadLineErr(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	cases := map[string]struct {
+		input io.Reader
+		line  string
+		err   error
+	}{
+		"line":            {input: strings.NewReader("foo\nbar\n"), line: "foo"},
+		"empty line":      {input: strings.NewReader("\n"), line: ""},
+		"no newline":      {input: strings.NewReader("foo"), line: "foo"},
+		"crlf":            {input: strings.NewReader("foo\r\nbar\r\n"), line: "foo"},
+		"crlf no newline": {input: strings.NewReader("foo\r"), line: "foo"},
+		"end of input":    {input: strings.NewReader(""), err: io.EOF},
+		"read error":      {input: errorReader{err: errors.New("broken pipe")}, err: errors.New("broken pipe")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			stdin = c.input
+			line, err := ReadLineErr(context.Background())
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.line, line)
+		})
+	}
+}
+
+func TestReadLineErr_Canceled(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = blockingReader{input: make(chan string)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	line, err := ReadLineErr(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, line)
+}
+
+func TestReadLineErr_CanceledKeepsInput(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	r := blockingReader{input: make(chan string)}
+	stdin = r
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err := ReadLineErr(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// The line that is entered after the first call was canceled must be
+	// returned by the next call instead of being discarded.
+	r.input <- "next line"
+	line, err := ReadLineErr(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "next line", line)
+}
+
 func TestReadLines(t *testing.T) {
 	defer func() { stdin = os.Stdin }()
 	ctx := context.Background()
@@ -103,6 +163,295 @@ func TestReadLinesCancel(t *testing.T) {
 	}
 }
 
+func TestReadLinesErr(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	cases := map[string]struct {
+		input io.Reader
+		lines []string
+		err   error
+	}{
+		"empty input": {input: strings.NewReader("")},
+		"lines":       {input: strings.NewReader("line 1\n\nline 3\n"), lines: []string{"line 1", "", "line 3"}},
+		"no newline":  {input: strings.NewReader("line 1\nline 2"), lines: []string{"line 1", "line 2"}},
+		"crlf":        {input: strings.NewReader("line 1\r\nline 2\r\n"), lines: []string{"line 1", "line 2"}},
+		"read error": {
+			input: io.MultiReader(strings.NewReader("line 1\n"), errorReader{err: errors.New("broken pipe")}),
+			lines: []string{"line 1"},
+			err:   errors.New("broken pipe"),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			stdin = c.input
+			lines, errs := ReadLinesErr(context.Background())
+			assert.Equal(t, c.lines, extract(lines))
+			assert.Equal(t, c.err, <-errs)
+		})
+	}
+}
+
+func TestReadLinesErr_Cancel(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := blockingReader{input: make(chan string, 1)}
+	r.input <- "line1"
+	stdin = r
+
+	lines, errs := ReadLinesErr(ctx)
+	assert.Equal(t, "line1", <-lines)
+	cancel()
+
+	assert.Empty(t, extract(lines))
+	assert.Equal(t, context.Canceled, <-errs)
+}
+
+func TestReadLinesErr_CancelKeepsInput(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("line 1\nline 2\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines, _ := ReadLinesErr(ctx)
+	assert.Equal(t, "line 1", <-lines)
+	cancel()
+
+	line, err := ReadLineErr(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "line 2", line)
+}
+
+func TestReadLineFrom(t *testing.T) {
+	r := strings.NewReader("foo\n")
+	line, err := ReadLineFrom(context.Background(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", line)
+}
+
+func TestReadLinesFrom(t *testing.T) {
+	r := strings.NewReader("foo\nbar\n")
+	lines, errs := ReadLinesFrom(context.Background(), r)
+	assert.Equal(t, []string{"foo", "bar"}, extract(lines))
+	assert.NoError(t, <-errs)
+}
+
+func TestReadLineErr_Successive(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("line 1\nline 2\nline 3\n")
+	ctx := context.Background()
+
+	line, err := ReadLineErr(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "line 1", line)
+
+	line, err = ReadLineFrom(ctx, stdin)
+	assert.NoError(t, err)
+	assert.Equal(t, "line 2", line)
+
+	assert.Equal(t, []string{"line 3"}, extract(ReadLines(ctx)))
+}
+
+func TestReader(t *testing.T) {
+	r := NewReader(strings.NewReader("line 1\nline 2\nline 3\n"))
+	ctx := context.Background()
+
+	line, err := r.ReadLine(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "line 1", line)
+
+	line, err = ReadLineFrom(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "line 2", line)
+
+	lines, errs := r.ReadLines(ctx)
+	assert.Equal(t, []string{"line 3"}, extract(lines))
+	assert.NoError(t, <-errs)
+
+	_, err = r.ReadLine(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReader_Read(t *testing.T) {
+	r := NewReader(strings.NewReader("line 1\nline 2\nline 3\n"))
+
+	line, err := r.ReadLine(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "line 1", line)
+
+	rest, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "line 2\nline 3\n", string(rest))
+}
+
+func TestReader_SetMaxLength(t *testing.T) {
+	long := strings.Repeat("x", 10000)
+	r := NewReader(strings.NewReader("12345\n123456\n" + long + "\nok\n" + long))
+	r.SetMaxLength(5)
+	ctx := context.Background()
+
+	line, err := r.ReadLine(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", line)
+
+	_, err = r.ReadLine(ctx)
+	assert.Equal(t, ErrLineTooLong, err)
+
+	_, err = r.ReadLine(ctx)
+	assert.Equal(t, ErrLineTooLong, err)
+
+	lines, errs := r.ReadLines(ctx)
+	assert.Equal(t, []string{"ok"}, extract(lines))
+	assert.Equal(t, ErrLineTooLong, <-errs)
+
+	_, err = r.ReadLine(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadUntil(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("foo;bar\nbaz;")
+	ctx := context.Background()
+
+	record, err := ReadUntil(ctx, ';')
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", record)
+
+	record, err = ReadUntil(ctx, ';')
+	assert.NoError(t, err)
+	assert.Equal(t, "bar\nbaz", record)
+
+	_, err = ReadUntil(ctx, ';')
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadRecords(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("./a\x00./b c\x00./d\ne\x00")
+
+	records, errs := ReadRecords(context.Background(), 0)
+	assert.Equal(t, []string{"./a", "./b c", "./d\ne"}, extract(records))
+	assert.NoError(t, <-errs)
+}
+
+func TestReader_MixedDelimiters(t *testing.T) {
+	ctx := context.Background()
+	input := blockingReader{input: make(chan string, 1), omitNewLine: true}
+	r := NewReader(input)
+
+	// A canceled line read leaves a pending read behind which must not
+	// swallow the delimiters of the next read.
+	canceled, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	_, err := r.ReadLine(canceled)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	input.input <- "a,b,c\n"
+	record, err := r.ReadUntil(ctx, ',')
+	assert.NoError(t, err)
+	assert.Equal(t, "a", record)
+
+	line, err := r.ReadLine(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "b,c", line)
+}
+
+func TestReader_MixedDelimitersEOF(t *testing.T) {
+	ctx := context.Background()
+	input := eofReader{input: make(chan string, 1)}
+	r := NewReader(input)
+
+	canceled, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	_, err := r.ReadLine(canceled)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// The EOF of the pending line read must not be lost, otherwise the
+	// last record would wait for more input like a terminal does after
+	// Ctrl+D.
+	input.input <- "a,b"
+	record, err := r.ReadUntil(ctx, ',')
+	assert.NoError(t, err)
+	assert.Equal(t, "a", record)
+
+	ctx, cancel = context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	record, err = r.ReadUntil(ctx, ',')
+	assert.NoError(t, err)
+	assert.Equal(t, "b", record)
+}
+
+func TestReadLinesBatch(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("1\n2\n3\n4\n5\n")
+
+	var batches [][]string
+	for batch := range ReadLinesBatch(context.Background(), 2) {
+		batches = append(batches, batch)
+	}
+
+	assert.Equal(t, [][]string{{"1", "2"}, {"3", "4"}, {"5"}}, batches)
+}
+
+func TestReader_ReadLinesBatch(t *testing.T) {
+	r := NewReader(strings.NewReader("1\n2\n3\n"))
+
+	batches, errs := r.ReadLinesBatch(context.Background(), 3)
+	assert.Equal(t, []string{"1", "2", "3"}, <-batches)
+	_, ok := <-batches
+	assert.False(t, ok)
+	assert.NoError(t, <-errs)
+
+	assert.Panics(t, func() { r.ReadLinesBatch(context.Background(), 0) })
+}
+
+func TestReadAll(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("line 1\nline 2\nno newline")
+
+	line, err := ReadLineErr(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "line 1", line)
+
+	data, err := ReadAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "line 2\nno newline", string(data))
+}
+
+func TestReader_ReadAll_Canceled(t *testing.T) {
+	input := blockingReader{input: make(chan string, 1), omitNewLine: true}
+	r := NewReader(input)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	data, err := r.ReadAll(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Empty(t, data)
+
+	// The input of the canceled read is not lost.
+	input.input <- "foo\nbar\n"
+	line, err := r.ReadLine(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", line)
+}
+
+func TestReadLinesAs(t *testing.T) {
+	defer func() { stdin = os.Stdin }()
+	stdin = strings.NewReader("1\n2\nthree\n4\n")
+	ctx := context.Background()
+
+	var ids []int
+	values, errs := ReadLinesAs(ctx, strconv.Atoi)
+	for id := range values {
+		ids = append(ids, id)
+	}
+
+	assert.Equal(t, []int{1, 2}, ids)
+	assert.EqualError(t, <-errs, `line 3: strconv.Atoi: parsing "three": invalid syntax`)
+
+	// Lines after the line that could not be parsed are not lost.
+	assert.Equal(t, []string{"4"}, extract(ReadLines(ctx)))
+}
+
 func extract(c <-chan string) []string {
 	result := make(chan []string)
 	go func() {
@@ -121,6 +470,14 @@ func extract(c <-chan string) []string {
 	}
 }
 
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
 type blockingReader struct {
 	input       chan string
 	omitNewLine bool
@@ -134,3 +491,14 @@ func (r blockingReader) Read(p []byte) (int, error) {
 
 	return strings.NewReader(s).Read(p)
 }
+
+// eofReader returns each input together with io.EOF like a terminal does if
+// Ctrl+D is pressed. The next read waits for more input.
+type eofReader struct {
+	input chan string
+}
+
+func (r eofReader) Read(p []byte) (int, error) {
+	n := copy(p, <-r.input)
+	return n, io.EOF
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"reflect"
+	"sync"
+)
+
+// redactedValue is printed instead of the values of fields with a "redact"
+// tag option.
+const redactedValue = "****"
+
+// redact returns a deep copy of v in which the values of all struct fields
+// with a "redact" tag option are masked. If v does not contain any such fields
+// it is returned unchanged. Cycles of pointers, maps and slices are preserved
+// in the copy.
+func redact(v interface{}) interface{} {
+	val := reflect.ValueOf(v)
+	if !val.IsValid() || !hasSecrets(val, map[copyKey]bool{}) {
+		return v
+	}
+	return redactValue(val, map[copyKey]reflect.Value{}).Interface()
+}
+
+// copyKey identifies a pointer, map or slice that has already been copied by
+// redactValue.
+type copyKey struct {
+	Pointer uintptr
+	Len     int
+	Type    reflect.Type
+}
+
+// secretInfo describes whether values of a type contain fields with a
+// "redact" tag option.
+type secretInfo struct {
+	// Redact is true if the type contains fields with a "redact" tag option
+	// without looking at the dynamic values of interfaces.
+	Redact bool
+
+	// Dynamic is true if the type contains interfaces whose dynamic values
+	// may contain such fields.
+	Dynamic bool
+}
+
+// secretCache contains the result of typeSecrets by type.
+var secretCache sync.Map // map[reflect.Type]secretInfo
+
+// typeSecrets returns whether values of type t contain fields with a "redact"
+// tag option. The result is cached.
+func typeSecrets(t reflect.Type) secretInfo {
+	if info, ok := secretCache.Load(t); ok {
+		return info.(secretInfo)
+	}
+
+	var info secretInfo
+	walkSecrets(t, &info, map[reflect.Type]bool{})
+	secretCache.Store(t, info)
+	return info
+}
+
+// walkSecrets sets the fields of info for the type t and all types it
+// contains.
+func walkSecrets(t reflect.Type, info *secretInfo, seen map[reflect.Type]bool) {
+	if seen[t] || info.Redact {
+		return
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		walkSecrets(t.Elem(), info, seen)
+	case reflect.Interface:
+		info.Dynamic = true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+
+			tag, _ := parseTableTag(f.Tag.Get("table"))
+			if tag.Redact {
+				info.Redact = true
+				return
+			}
+			walkSecrets(f.Type, info, seen)
+		}
+	}
+}
+
+// containsSecrets returns true if values of type t may contain fields with a
+// "redact" tag option, either directly or via the dynamic values of
+// interfaces.
+func containsSecrets(t reflect.Type) bool {
+	info := typeSecrets(t)
+	return info.Redact || info.Dynamic
+}
+
+// hasSecrets returns true if v contains fields with a "redact" tag option.
+// Interfaces are only walked if their dynamic values may contain such fields,
+// so values such as decoded JSON (e.g. map[string]interface{}) are checked
+// without being copied. Seen contains all pointers, maps and slices that have
+// been checked so far.
+func hasSecrets(v reflect.Value, seen map[copyKey]bool) bool {
+	info := typeSecrets(v.Type())
+	if info.Redact || !info.Dynamic {
+		return info.Redact
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return false
+		}
+		key := copyKey{Pointer: v.Pointer(), Type: v.Type()}
+		if v.Kind() == reflect.Slice {
+			key.Len = v.Len()
+		}
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !v.IsNil() && hasSecrets(v.Elem(), seen)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if hasSecrets(v.Index(i), seen) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if hasSecrets(iter.Value(), seen) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" && hasSecrets(v.Field(i), seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// redactValue returns a deep copy of v in which all redacted fields are
+// masked. Values that cannot contain secrets are not copied. Copies contains
+// the copies of all pointers, maps and slices that have been copied so far.
+func redactValue(v reflect.Value, copies map[copyKey]reflect.Value) reflect.Value {
+	if !containsSecrets(v.Type()) {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := copyKey{Pointer: v.Pointer(), Type: v.Type()}
+		if c, ok := copies[key]; ok {
+			return c
+		}
+		c := reflect.New(v.Type().Elem())
+		copies[key] = c
+		c.Elem().Set(redactValue(v.Elem(), copies))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(redactValue(v.Elem(), copies))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		key := copyKey{Pointer: v.Pointer(), Len: v.Len(), Type: v.Type()}
+		if c, ok := copies[key]; ok {
+			return c
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		copies[key] = c
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(redactValue(v.Index(i), copies))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(redactValue(v.Index(i), copies))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := copyKey{Pointer: v.Pointer(), Type: v.Type()}
+		if c, ok := copies[key]; ok {
+			return c
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		copies[key] = c
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), redactValue(iter.Value(), copies))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+
+			tag, _ := parseTableTag(f.Tag.Get("table"))
+			if tag.Redact {
+				mask(c.Field(i))
+			} else {
+				c.Field(i).Set(redactValue(v.Field(i), copies))
+			}
+		}
+		return c
+	}
+
+	return v
+}
+
+// mask replaces the value of v with redactedValue if it is a non-empty string
+// or a pointer to a string. All other values are set to their zero value.
+func mask(v reflect.Value) {
+	switch {
+	case v.IsZero():
+		return
+	case v.Kind() == reflect.String:
+		v.SetString(redactedValue)
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.String:
+		p := reflect.New(v.Type().Elem())
+		p.Elem().SetString(redactedValue)
+		v.Set(p)
+	default:
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
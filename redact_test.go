@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type credentials struct {
+	User   string `json:"user" yaml:"user"`
+	Token  string `json:"token" yaml:"token" table:",redact"`
+	PIN    *int   `json:"pin,omitempty" yaml:"pin,omitempty" table:",redact"`
+	Backup string `json:"backup,omitempty" yaml:"backup,omitempty" table:",redact"`
+}
+
+func TestPrint_Redact(t *testing.T) {
+	pin := 1234
+	creds := []credentials{{User: "foo", Token: "secret", PIN: &pin}}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", creds, out))
+	expected := []string{
+		"USER    TOKEN   PIN     BACKUP",
+		"foo     ****    ****",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("json", creds, out))
+	assert.JSONEq(t, `[{"user": "foo", "token": "****"}]`, out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("yaml", map[string]interface{}{"creds": &creds[0]}, out))
+	assert.Equal(t, "creds:\n  user: foo\n  token: '****'\n\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("raw", creds[0], out))
+	assert.NotContains(t, out.String(), "secret")
+
+	// The original value must not be modified.
+	assert.Equal(t, "secret", creds[0].Token)
+	assert.Equal(t, 1234, *creds[0].PIN)
+
+	out.Reset()
+	require.NoError(t, PrintWriter("json", creds, out, ShowSecrets()))
+	assert.JSONEq(t, `[{"user": "foo", "token": "secret", "pin": 1234}]`, out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("detail", creds, out, ShowSecrets(), Columns("token")))
+	assert.Equal(t, "TOKEN: secret\n", out.String())
+}
+
+func TestRedact_Cycle(t *testing.T) {
+	type node struct {
+		Name   string
+		Secret string `table:",redact"`
+		Parent *node
+		Kids   []*node
+	}
+
+	root := &node{Name: "root", Secret: "s3cret"}
+	kid := &node{Name: "kid", Secret: "s3cret", Parent: root}
+	root.Kids = []*node{kid}
+
+	redacted := redact(root).(*node)
+	assert.Equal(t, "****", redacted.Secret)
+	assert.Equal(t, "****", redacted.Kids[0].Secret)
+	assert.Same(t, redacted, redacted.Kids[0].Parent)
+	assert.Equal(t, "s3cret", root.Secret, "original is not modified")
+
+	list := []interface{}{"a", nil}
+	list[1] = list
+	assert.NotPanics(t, func() { redact(list) })
+}
+
+func TestRedact_Interfaces(t *testing.T) {
+	data := map[string]interface{}{
+		"name":  "foo",
+		"tags":  []interface{}{"a", 1.5, map[string]interface{}{"b": true}},
+		"count": 3,
+	}
+
+	// Values without secrets are not copied.
+	redacted := redact(data)
+	assert.Equal(t, reflect.ValueOf(data).Pointer(), reflect.ValueOf(redacted).Pointer())
+
+	data["creds"] = []interface{}{credentials{User: "foo", Token: "secret"}}
+	redacted = redact(data)
+	assert.True(t, reflect.ValueOf(data).Pointer() != reflect.ValueOf(redacted).Pointer())
+	assert.Equal(t, "****", redacted.(map[string]interface{})["creds"].([]interface{})[0].(credentials).Token)
+	assert.Equal(t, "secret", data["creds"].([]interface{})[0].(credentials).Token)
+}
+
+func BenchmarkRedact(b *testing.B) {
+	rows := make([]interface{}, 10000)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"id": i, "name": "foo", "tags": []interface{}{"a", "b"}}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		redact(rows)
+	}
+}
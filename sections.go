@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// A Section is a labeled value that is printed by PrintSections.
+type Section struct {
+	// Key is the key of the value in the "json" and "yaml" encodings.
+	Key string
+
+	// Title is printed above the value by the "table" and "detail"
+	// encodings. If it is empty, the key is used instead.
+	Title string
+
+	// Value is the value of the section.
+	Value interface{}
+
+	// Options are only applied to the value of this section.
+	Options []Option
+}
+
+// PrintSections prints multiple labeled values to the standard output. The
+// "json" and "yaml" encodings print a single document that contains the value
+// of each section by its key in the given order. All other encodings print the
+// values one after another separated by an empty line. The "table" and
+// "detail" encodings print the title of each section above its value.
+func PrintSections(encoding string, sections []Section, opts ...Option) error {
+	return PrintSectionsWriter(encoding, sections, os.Stdout, opts...)
+}
+
+// PrintSectionsWriter is like PrintSections but lets the caller inject an
+// io.Writer.
+func PrintSectionsWriter(encoding string, sections []Section, w io.Writer, opts ...Option) error {
+	switch strings.ToLower(encoding) {
+	case "json", "yml", "yaml":
+		// The options of the sections are ignored since they cannot be
+		// applied to parts of the document.
+		return PrintWriter(encoding, sectionDocument(sections), w, opts...)
+	}
+
+	for i, s := range sections {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+
+		title := s.Title
+		if title == "" {
+			title = s.Key
+		}
+
+		all := append(append(append([]Option{}, opts...), Title(title)), s.Options...)
+		if err := PrintWriter(encoding, s.Value, w, all...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// A sectionDocument is encoded as an object that contains the values of all
+// sections by their key. The order of the sections is preserved.
+type sectionDocument []Section
+
+// MarshalJSON implements the json.Marshaler interface. HTML characters are not
+// escaped since the encoder that calls this method escapes them if necessary.
+func (d sectionDocument) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	buf.WriteString("{")
+	for i, s := range d {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		if err := enc.Encode(s.Key); err != nil {
+			return nil, err
+		}
+		buf.WriteString(":")
+		if err := enc.Encode(s.Value); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (d sectionDocument) MarshalYAML() (interface{}, error) {
+	doc := make(yaml.MapSlice, len(d))
+	for i, s := range d {
+		doc[i] = yaml.MapItem{Key: s.Key, Value: s.Value}
+	}
+	return doc, nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSections(t *testing.T) {
+	type node struct {
+		Name  string
+		Ready bool
+	}
+
+	type pod struct {
+		Name     string
+		Restarts int
+	}
+
+	sections := []Section{
+		{Key: "nodes", Title: "Nodes", Value: []node{{"a", true}}},
+		{Key: "pods", Value: []pod{{"api", 1}, {"web", 0}}, Options: []Option{Columns("name")}},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintSectionsWriter("table", sections, out))
+	expected := []string{
+		"Nodes",
+		"NAME    READY",
+		"a       true",
+		"",
+		"pods",
+		"NAME",
+		"api",
+		"web",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintSectionsWriter("json", sections, out, JSONIndent("")))
+	assert.Equal(t, `{"nodes":[{"Name":"a","Ready":true}],"pods":[{"Name":"api","Restarts":1},{"Name":"web","Restarts":0}]}`+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintSectionsWriter("yaml", sections[:1], out))
+	assert.Equal(t, "nodes:\n- name: a\n  ready: true\n\n", out.String())
+}
+
+func TestPrintSections_Redact(t *testing.T) {
+	sections := []Section{{Key: "creds", Value: credentials{User: "<foo>", Token: "secret"}}}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintSectionsWriter("json", sections, out))
+	assert.JSONEq(t, `{"creds": {"user": "<foo>", "token": "****"}}`, out.String())
+	assert.Contains(t, out.String(), "<foo>")
+}
@@ -0,0 +1,498 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Select writes the prompt and a numbered list of the given options to
+// stderr and lets the user choose one of them. It returns the index and the
+// value of the chosen option.
+//
+// If stdin and stderr are terminals, the option can be chosen with the arrow
+// keys (or j and k) and confirmed with enter. Otherwise the user is asked for
+// the number of the option like AskValidated does, so all prompt options that
+// apply to AskValidated can be used. Instead of the number the user may also
+// enter the option itself.
+func Select(ctx context.Context, prompt string, options []string, opts ...PromptOption) (int, string, error) {
+	if len(options) == 0 {
+		return -1, "", errors.New("cannot select from an empty list of options")
+	}
+
+	var i int
+	ok, err := withRawStdin(func() (err error) {
+		i, err = selectInteractive(ctx, stdinLineReader(), stderr, prompt, options)
+		return err
+	})
+	if err != nil {
+		return -1, "", err
+	}
+	if ok {
+		return i, options[i], nil
+	}
+
+	printOptions(prompt, options)
+	i, err = askAs(ctx, fmt.Sprintf("Enter a number (1-%d): ", len(options)), func(s string) (int, error) {
+		return parseChoice(s, options)
+	}, opts)
+	if err != nil {
+		return -1, "", err
+	}
+	return i, options[i], nil
+}
+
+// MultiSelect writes the prompt and a numbered list of the given options to
+// stderr like Select does but lets the user choose any number of them. It
+// returns the indexes and the values of the chosen options in the order of
+// the options.
+//
+// If stdin and stderr are terminals, options are toggled with space and the
+// choice is confirmed with enter. Otherwise the user is asked for the numbers
+// of the options separated by commas like AskValidated does. An empty answer
+// chooses no option.
+func MultiSelect(ctx context.Context, prompt string, options []string, opts ...PromptOption) ([]int, []string, error) {
+	if len(options) == 0 {
+		return nil, nil, errors.New("cannot select from an empty list of options")
+	}
+
+	var indexes []int
+	ok, err := withRawStdin(func() (err error) {
+		indexes, err = multiSelectInteractive(ctx, stdinLineReader(), stderr, prompt, options)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !ok {
+		printOptions(prompt, options)
+		indexes, err = askAs(ctx, fmt.Sprintf("Enter numbers separated by commas (1-%d): ", len(options)), func(s string) ([]int, error) {
+			return parseChoices(s, options)
+		}, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	values := make([]string, len(indexes))
+	for i, n := range indexes {
+		values[i] = options[n]
+	}
+	return indexes, values, nil
+}
+
+// FuzzySelect lets the user choose one of the given options like Select does
+// but is meant for long lists of options. If stdin and stderr are terminals,
+// the options are filtered while the user types and only the options that
+// match the typed characters in the same order (e.g. "kbsys" matches
+// "kube-system") are shown. The arrow keys move between the matching options
+// and enter chooses one of them.
+//
+// Otherwise the user is asked for the option like AskValidated does. The
+// answer may be the option itself or a filter that matches exactly one of the
+// options.
+func FuzzySelect(ctx context.Context, prompt string, options []string, opts ...PromptOption) (int, string, error) {
+	if len(options) == 0 {
+		return -1, "", errors.New("cannot select from an empty list of options")
+	}
+
+	var i int
+	ok, err := withRawStdin(func() (err error) {
+		i, err = fuzzySelectInteractive(ctx, stdinLineReader(), stderr, prompt, options)
+		return err
+	})
+	if err != nil {
+		return -1, "", err
+	}
+	if ok {
+		return i, options[i], nil
+	}
+
+	i, err = askAs(ctx, prompt+" ", func(s string) (int, error) {
+		for i, option := range options {
+			if strings.EqualFold(s, option) {
+				return i, nil
+			}
+		}
+
+		matches := fuzzyFilter(s, options)
+		switch {
+		case len(matches) == 1:
+			return matches[0], nil
+		case len(matches) == 0:
+			return 0, fmt.Errorf("%q does not match any option", s)
+		default:
+			return 0, fmt.Errorf("%q matches %d options, please be more specific", s, len(matches))
+		}
+	}, opts)
+	if err != nil {
+		return -1, "", err
+	}
+	return i, options[i], nil
+}
+
+// maxFuzzyMatches is the number of matching options that are shown at once by
+// FuzzySelect.
+const maxFuzzyMatches = 10
+
+// fuzzySelectInteractive lets the user filter the options by typing and choose
+// one of the matches with the arrow keys. The terminal must be in raw mode.
+func fuzzySelectInteractive(ctx context.Context, lr *lineReader, w io.Writer, prompt string, options []string) (int, error) {
+	color := MessageColor.enabled(w)
+	var query []rune
+	matches := fuzzyFilter("", options)
+	cursor, offset, lines := 0, 0, 0
+
+	render := func() {
+		if lines > 0 {
+			fmt.Fprintf(w, "\x1b[%dA", lines)
+		}
+		fmt.Fprint(w, "\r\x1b[J"+prompt+" "+string(query)+"\r\n")
+
+		if cursor < offset {
+			offset = cursor
+		}
+		if cursor >= offset+maxFuzzyMatches {
+			offset = cursor - maxFuzzyMatches + 1
+		}
+
+		lines = 1
+		for i := offset; i < len(matches) && i < offset+maxFuzzyMatches; i++ {
+			line := "  " + options[matches[i]]
+			if i == cursor {
+				line = "> " + options[matches[i]]
+				if color {
+					line = Cyan.apply(line)
+				}
+			}
+			fmt.Fprint(w, line+"\r\n")
+			lines++
+		}
+		if len(matches) > maxFuzzyMatches {
+			fmt.Fprintf(w, "  (%d of %d matches)\r\n", maxFuzzyMatches, len(matches))
+			lines++
+		}
+	}
+
+	filter := func() {
+		matches = fuzzyFilter(string(query), options)
+		cursor, offset = 0, 0
+	}
+
+	render()
+	err := readKeyPresses(ctx, lr, func(k keyPress) (bool, error) {
+		switch k.Key {
+		case keyEnter:
+			return len(matches) > 0, nil
+		case keyInterrupt:
+			ReceiveSignal(os.Interrupt)
+			return false, ErrInterrupted
+		case keyEOF:
+			return false, io.EOF
+		case keyUp:
+			if cursor > 0 {
+				cursor--
+			}
+		case keyDown:
+			if cursor < len(matches)-1 {
+				cursor++
+			}
+		case keyBackspace:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				filter()
+			}
+		case keyClear:
+			query = query[:0]
+			filter()
+		case keyRune:
+			query = append(query, k.R)
+			filter()
+		default:
+			return false, nil
+		}
+
+		render()
+		return false, nil
+	})
+
+	fmt.Fprintf(w, "\x1b[%dA\r\x1b[J", lines)
+	if err != nil {
+		fmt.Fprint(w, prompt+"\r\n")
+		return -1, err
+	}
+
+	i := matches[cursor]
+	fmt.Fprint(w, prompt+" "+options[i]+"\r\n")
+	return i, nil
+}
+
+// fuzzyFilter returns the indexes of all options that contain the runes of
+// the query in the same order, ignoring case. The best matches come first:
+// options whose matching runes are closer together and closer to the start of
+// the option are ranked higher. All options match an empty query.
+func fuzzyFilter(query string, options []string) []int {
+	type match struct {
+		index, span, start int
+	}
+
+	var matches []match
+	for i, option := range options {
+		if start, span, ok := fuzzyMatch(query, option); ok {
+			matches = append(matches, match{index: i, span: span, start: start})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.span != b.span {
+			return a.span < b.span
+		}
+		return a.start < b.start
+	})
+
+	indexes := make([]int, len(matches))
+	for i, m := range matches {
+		indexes[i] = m.index
+	}
+	return indexes
+}
+
+// fuzzyMatch reports whether s contains the runes of the query in the same
+// order, ignoring case. It returns the rune offset of the first matching rune
+// and the number of runes between the first and the last matching rune.
+func fuzzyMatch(query, s string) (start, span int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, 0, true
+	}
+
+	start = -1
+	i := 0
+	for pos, r := range []rune(s) {
+		if unicode.ToLower(r) != q[i] {
+			continue
+		}
+		if start < 0 {
+			start = pos
+		}
+		i++
+		if i == len(q) {
+			return start, pos - start + 1, true
+		}
+	}
+	return 0, 0, false
+}
+
+// withRawStdin calls fn while stdin is in raw mode if stdin and stderr are
+// terminals. It returns false without calling fn otherwise.
+func withRawStdin(fn func() error) (bool, error) {
+	if !isTerminal(stderr) {
+		return false, nil
+	}
+
+	restore, ok, err := makeStdinRaw()
+	if !ok || err != nil {
+		return false, err
+	}
+	defer restore()
+
+	return true, fn()
+}
+
+// printOptions writes the prompt and the numbered options to stderr.
+func printOptions(prompt string, options []string) {
+	fmt.Fprintln(stderr, prompt)
+	width := len(strconv.Itoa(len(options)))
+	for i, option := range options {
+		fmt.Fprintf(stderr, "%*d) %s\n", width, i+1, option)
+	}
+}
+
+// parseChoices returns the sorted indexes of the options that were chosen by
+// entering a comma separated list of their numbers or the options themselves.
+func parseChoices(s string, options []string) ([]int, error) {
+	chosen := make([]bool, len(options))
+	for _, choice := range strings.Split(s, ",") {
+		choice = strings.TrimSpace(choice)
+		if choice == "" {
+			continue
+		}
+
+		i, err := parseChoice(choice, options)
+		if err != nil {
+			return nil, err
+		}
+		chosen[i] = true
+	}
+
+	var indexes []int
+	for i, ok := range chosen {
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// parseChoice returns the index of the option that was chosen by entering its
+// number or the option itself.
+func parseChoice(s string, options []string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= len(options) {
+		return n - 1, nil
+	}
+	for i, option := range options {
+		if strings.EqualFold(s, option) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%q is not a valid choice", s)
+}
+
+// selectInteractive lets the user choose one of the options with the arrow
+// keys. The terminal must be in raw mode.
+func selectInteractive(ctx context.Context, lr *lineReader, w io.Writer, prompt string, options []string) (int, error) {
+	m := newMenu(w, prompt, options, false)
+	err := readKeyPresses(ctx, lr, func(k keyPress) (bool, error) {
+		if k.Key == keyEnter {
+			return true, nil
+		}
+		return false, m.handle(k)
+	})
+
+	if err != nil {
+		m.close("")
+		return -1, err
+	}
+	m.close(options[m.cursor])
+	return m.cursor, nil
+}
+
+// multiSelectInteractive lets the user toggle options with the arrow keys and
+// space. The terminal must be in raw mode.
+func multiSelectInteractive(ctx context.Context, lr *lineReader, w io.Writer, prompt string, options []string) ([]int, error) {
+	m := newMenu(w, prompt, options, true)
+	err := readKeyPresses(ctx, lr, func(k keyPress) (bool, error) {
+		if k.Key == keyEnter {
+			return true, nil
+		}
+		return false, m.handle(k)
+	})
+
+	if err != nil {
+		m.close("")
+		return nil, err
+	}
+
+	var indexes []int
+	var values []string
+	for i, ok := range m.checked {
+		if ok {
+			indexes = append(indexes, i)
+			values = append(values, options[i])
+		}
+	}
+	m.close(strings.Join(values, ", "))
+	return indexes, nil
+}
+
+// A menu is an interactive list of options on a terminal in raw mode. It is
+// redrawn after each key press and replaced by the chosen options when it is
+// closed.
+type menu struct {
+	w       io.Writer
+	prompt  string
+	options []string
+	cursor  int
+	checked []bool // the checked options or nil if only one can be chosen
+	color   bool
+}
+
+// newMenu writes the prompt and the options to w and returns the menu. If
+// multi is true, each option has a checkbox.
+func newMenu(w io.Writer, prompt string, options []string, multi bool) *menu {
+	m := &menu{w: w, prompt: prompt, options: options, color: MessageColor.enabled(w)}
+	if multi {
+		m.checked = make([]bool, len(options))
+	}
+
+	fmt.Fprint(w, prompt+"\r\n")
+	m.render()
+	return m
+}
+
+// render writes the options below the prompt.
+func (m *menu) render() {
+	for i, option := range m.options {
+		if m.checked != nil {
+			if m.checked[i] {
+				option = "[x] " + option
+			} else {
+				option = "[ ] " + option
+			}
+		}
+
+		line := "  " + option
+		if i == m.cursor {
+			line = "> " + option
+			if m.color {
+				line = Cyan.apply(line)
+			}
+		}
+		fmt.Fprint(m.w, "\r\x1b[K"+line+"\r\n")
+	}
+}
+
+// handle moves the cursor according to the given key press and redraws the
+// menu. It returns ErrInterrupted if Ctrl+C was pressed and io.EOF if Ctrl+D
+// was pressed.
+func (m *menu) handle(k keyPress) error {
+	switch {
+	case k.Key == keyInterrupt:
+		ReceiveSignal(os.Interrupt)
+		return ErrInterrupted
+	case k.Key == keyEOF:
+		return io.EOF
+	case k.Key == keyUp || k.Key == keyRune && k.R == 'k':
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case k.Key == keyDown || k.Key == keyRune && k.R == 'j':
+		if m.cursor < len(m.options)-1 {
+			m.cursor++
+		}
+	case k.Key == keyHome:
+		m.cursor = 0
+	case k.Key == keyEnd:
+		m.cursor = len(m.options) - 1
+	case k.Key == keyRune && k.R >= '1' && k.R <= '9':
+		if n := int(k.R - '1'); n < len(m.options) {
+			m.cursor = n
+		}
+	case k.Key == keyRune && k.R == ' ' && m.checked != nil:
+		m.checked[m.cursor] = !m.checked[m.cursor]
+	default:
+		return nil
+	}
+
+	fmt.Fprintf(m.w, "\x1b[%dA", len(m.options))
+	m.render()
+	return nil
+}
+
+// close replaces the menu by the prompt followed by the given answer.
+func (m *menu) close(answer string) {
+	fmt.Fprintf(m.w, "\x1b[%dA\r\x1b[J", len(m.options)+1)
+	if answer == "" {
+		fmt.Fprint(m.w, m.prompt+"\r\n")
+		return
+	}
+	fmt.Fprint(m.w, m.prompt+" "+answer+"\r\n")
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelect(t *testing.T) {
+	out := mockPrompt(t, "4\nProd\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+
+	i, option, err := Select(context.Background(), "Environment:", []string{"dev", "staging", "prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, i)
+	assert.Equal(t, "prod", option)
+	assert.Equal(t, "Environment:\n1) dev\n2) staging\n3) prod\n"+
+		"Enter a number (1-3): ✗ \"4\" is not a valid choice\nEnter a number (1-3): ", out.String())
+
+	_, _, err = Select(context.Background(), "Environment:", nil)
+	assert.EqualError(t, err, "cannot select from an empty list of options")
+}
+
+func TestSelectInteractive(t *testing.T) {
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+
+	lr := newLineReader(strings.NewReader("\x1b[B\x1b[Bj\x1b[A\r"))
+	out := new(bytes.Buffer)
+
+	i, err := selectInteractive(context.Background(), lr, out, "Environment:", []string{"dev", "staging", "prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+	assert.True(t, strings.HasPrefix(out.String(), "Environment:\r\n\r\x1b[K> dev\r\n\r\x1b[K  staging\r\n\r\x1b[K  prod\r\n"))
+	assert.True(t, strings.HasSuffix(out.String(), "\x1b[4A\r\x1b[JEnvironment: staging\r\n"))
+
+	lr = newLineReader(strings.NewReader("\x1b[A\x033\r"))
+	_, err = selectInteractive(context.Background(), lr, out, "Environment:", []string{"dev"})
+	assert.Equal(t, ErrInterrupted, err)
+}
+
+func TestMultiSelect(t *testing.T) {
+	out := mockPrompt(t, "1,9\n3, api,1\n\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+	ctx := context.Background()
+	options := []string{"api", "worker", "cron"}
+
+	indexes, values, err := MultiSelect(ctx, "Restart:", options)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, indexes)
+	assert.Equal(t, []string{"api", "cron"}, values)
+	assert.Equal(t, "Restart:\n1) api\n2) worker\n3) cron\n"+
+		"Enter numbers separated by commas (1-3): ✗ \"9\" is not a valid choice\n"+
+		"Enter numbers separated by commas (1-3): ", out.String())
+
+	indexes, values, err = MultiSelect(ctx, "Restart:", options)
+	assert.NoError(t, err)
+	assert.Empty(t, indexes)
+	assert.Empty(t, values)
+}
+
+func TestMultiSelectInteractive(t *testing.T) {
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+
+	lr := newLineReader(strings.NewReader(" jj  \x1b[A \r"))
+	out := new(bytes.Buffer)
+
+	indexes, err := multiSelectInteractive(context.Background(), lr, out, "Restart:", []string{"api", "worker", "cron"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, indexes)
+	assert.True(t, strings.HasPrefix(out.String(), "Restart:\r\n\r\x1b[K> [ ] api\r\n\r\x1b[K  [ ] worker\r\n"))
+	assert.True(t, strings.HasSuffix(out.String(), "\x1b[4A\r\x1b[JRestart: api, worker\r\n"))
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	options := []string{"default", "kube-system", "kube-public", "monitoring", "Kbsystem"}
+
+	assert.Equal(t, []int{4, 1}, fuzzyFilter("kbsys", options))
+	assert.Equal(t, []int{1, 2}, fuzzyFilter("kube", options))
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, fuzzyFilter("", options))
+	assert.Empty(t, fuzzyFilter("xyz", options))
+}
+
+func TestFuzzySelect(t *testing.T) {
+	out := mockPrompt(t, "kube\nkbpub\n")
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+
+	i, option, err := FuzzySelect(context.Background(), "Namespace:", []string{"default", "kube-system", "kube-public"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, i)
+	assert.Equal(t, "kube-public", option)
+	assert.Equal(t, "Namespace: ✗ \"kube\" matches 2 options, please be more specific\nNamespace: ", out.String())
+}
+
+func TestFuzzySelectInteractive(t *testing.T) {
+	defer func(c ColorMode) { MessageColor = c }(MessageColor)
+	MessageColor = ColorNever
+
+	options := make([]string, 20)
+	for i := range options {
+		options[i] = fmt.Sprintf("ns-%02d", i)
+	}
+	lr := newLineReader(strings.NewReader("ns-1x\x7f\x1b[B\x1b[B\r"))
+	out := new(bytes.Buffer)
+
+	i, err := fuzzySelectInteractive(context.Background(), lr, out, "Namespace:", options)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, i)
+	assert.True(t, strings.HasPrefix(out.String(), "\r\x1b[JNamespace: \r\n> ns-00\r\n  ns-01\r\n"))
+	assert.Contains(t, out.String(), "  (10 of 20 matches)\r\n")
+	assert.True(t, strings.HasSuffix(out.String(), "Namespace: ns-12\r\n"))
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// isStream returns true if v is a channel that can be received from.
+func isStream(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Chan && t.ChanDir()&reflect.RecvDir != 0
+}
+
+// isIterator returns true if v is a non-nil function with the signature of an
+// iter.Seq or iter.Seq2 (i.e. func(yield func(V) bool) or
+// func(yield func(K, V) bool)).
+func isIterator(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 || reflect.ValueOf(v).IsNil() {
+		return false
+	}
+
+	yield := t.In(0)
+	return yield.Kind() == reflect.Func &&
+		(yield.NumIn() == 1 || yield.NumIn() == 2) &&
+		yield.NumOut() == 1 && yield.Out(0).Kind() == reflect.Bool
+}
+
+// iterate runs the iterator seq in a new goroutine and returns a channel which
+// receives all values that are yielded by it. Keys of an iter.Seq2 are
+// discarded. The channel is closed when the iterator returns. The returned stop
+// function must be called once the channel is no longer read so that the
+// iterator is stopped.
+func iterate(seq interface{}) (ch interface{}, stop func()) {
+	s := reflect.ValueOf(seq)
+	yieldType := s.Type().In(0)
+	elem := yieldType.In(yieldType.NumIn() - 1)
+
+	c := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, elem), 0)
+	done := make(chan struct{})
+	yield := reflect.MakeFunc(yieldType, func(args []reflect.Value) []reflect.Value {
+		chosen, _, _ := reflect.Select([]reflect.SelectCase{
+			{Dir: reflect.SelectSend, Chan: c, Send: args[len(args)-1]},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)},
+		})
+		return []reflect.Value{reflect.ValueOf(chosen == 0).Convert(yieldType.Out(0))}
+	})
+
+	go func() {
+		defer c.Close()
+		s.Call([]reflect.Value{yield})
+	}()
+
+	var once sync.Once
+	return c.Interface(), func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// collectStream receives all values from the channel ch until it is closed and
+// returns them as slice.
+func collectStream(ch interface{}) interface{} {
+	c := reflect.ValueOf(ch)
+	values := reflect.MakeSlice(reflect.SliceOf(c.Type().Elem()), 0, 0)
+	for {
+		v, ok := c.Recv()
+		if !ok {
+			return values.Interface()
+		}
+		values = reflect.Append(values, v)
+	}
+}
+
+// printTableStream prints all values that are received from the channel ch as
+// rows of a table until the channel is closed. Rows are printed in batches so
+// the user does not have to wait until the channel is closed. A new batch is
+// printed whenever the flush interval has passed since the first row of the
+// batch was received. The columns of all batches stay aligned unless a later
+// batch contains wider cells than all previous batches.
+func printTableStream(ch interface{}, w io.Writer, opts *options) error {
+	c := reflect.ValueOf(ch)
+	t := baseType(c.Type().Elem())
+	if t.Kind() != reflect.Struct {
+		if err := writeTitle(w, opts.useColor(w), opts); err != nil {
+			return err
+		}
+		for {
+			v, ok := c.Recv()
+			if !ok {
+				return nil
+			}
+			if _, err := fmt.Fprintln(w, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.sortBy != "" || opts.groupBy != "" || opts.splitBy != "" {
+		return errors.New("cannot sort, group or split a stream")
+	}
+
+	fields, err := tableFields(t, opts)
+	if err != nil {
+		return err
+	}
+
+	fields, err = selectColumns(fields, nil, opts)
+	if err != nil {
+		return err
+	}
+
+	var (
+		printed int
+		widths  []int
+	)
+
+	flush := func(rows []reflect.Value) error {
+		if printed > 0 && len(rows) == 0 {
+			return nil
+		}
+
+		newLayout := func(fields []field) *tableLayout {
+			layout := newTableLayout(w, fields, rows, nil, nil, opts)
+			layout.border = BorderStyle{}
+			layout.widths = widths
+			layout.skipHeader = printed > 0
+			return layout
+		}
+
+		// Columns can only be removed before the header has been printed.
+		var layout *tableLayout
+		if printed == 0 {
+			fields, layout = fitTable(fields, newLayout)
+		} else {
+			layout = newLayout(fields)
+		}
+
+		if opts.rowNumbers {
+			for i := range layout.records {
+				layout.records[i][0] = strconv.Itoa(printed + i + 1)
+			}
+		}
+
+		if printed == 0 {
+			if err := writeTitle(w, opts.useColor(w), opts); err != nil {
+				return err
+			}
+		}
+		if err := layout.write(w); err != nil {
+			return err
+		}
+
+		widths = layout.widths
+		printed += len(rows)
+		return nil
+	}
+
+	for {
+		// With a limit, no more than one row beyond the limit is received,
+		// which is enough to know that rows have been omitted. The stream
+		// may be unbounded, so the omitted rows are not counted.
+		var n int
+		if opts.limit > 0 {
+			n = opts.limit - printed + 1
+		}
+
+		rows, ok := receiveBatch(c, opts.flushInterval, n)
+		if printed == 0 && len(rows) == 0 && opts.noResults != "" {
+			return writeNoResults(w, opts)
+		}
+
+		var omitted bool
+		if opts.limit > 0 && printed+len(rows) > opts.limit {
+			rows = rows[:opts.limit-printed]
+			omitted = true
+		}
+
+		if err := flush(rows); err != nil {
+			return err
+		}
+
+		if omitted {
+			return writeLimitMessage(w, -1, opts)
+		}
+
+		if !ok {
+			return nil
+		}
+	}
+}
+
+// receiveBatch receives values from the channel c until the given interval has
+// passed since the first value was received or n values have been received.
+// If n is 0, the number of values is not limited. It returns false if the
+// channel has been closed.
+func receiveBatch(c reflect.Value, interval time.Duration, n int) ([]reflect.Value, bool) {
+	v, ok := c.Recv()
+	if !ok {
+		return nil, false
+	}
+
+	rows := []reflect.Value{v}
+	if n == 1 {
+		return rows, true
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: c},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)},
+	}
+
+	for {
+		chosen, v, ok := reflect.Select(cases)
+		switch {
+		case chosen == 1:
+			return rows, true
+		case !ok:
+			return rows, false
+		}
+		rows = append(rows, v)
+		if len(rows) == n {
+			return rows, true
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamRow struct {
+	Name string
+	Age  int
+}
+
+func TestPrintTableStream(t *testing.T) {
+	c := make(chan streamRow, 3)
+	c <- streamRow{Name: "Foo", Age: 1}
+	c <- streamRow{Name: "Bar", Age: 2}
+	close(c)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", c, out, RowNumbers()))
+	expected := []string{
+		"#       NAME    AGE",
+		"1       Foo     1",
+		"2       Bar     2",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTableStream_Batches(t *testing.T) {
+	c := make(chan *streamRow)
+	go func() {
+		c <- &streamRow{Name: "Foo", Age: 1}
+		time.Sleep(20 * time.Millisecond)
+		c <- &streamRow{Name: "A much longer name", Age: 2}
+		c <- nil
+		close(c)
+	}()
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", (<-chan *streamRow)(c), out, FlushInterval(time.Millisecond)))
+	expected := []string{
+		"NAME    AGE",
+		"Foo     1",
+		"A much longer name  2",
+		"-                   -",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTableStream_Errors(t *testing.T) {
+	c := make(chan streamRow)
+	close(c)
+
+	err := PrintWriter("table", c, new(bytes.Buffer), SortBy("name", false))
+	assert.EqualError(t, err, "cannot sort, group or split a stream")
+}
+
+func TestPrintDetailStream(t *testing.T) {
+	c := make(chan streamRow, 2)
+	c <- streamRow{Name: "Foo", Age: 1}
+	c <- streamRow{Name: "Bar", Age: 2}
+	close(c)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("detail", c, out))
+	expected := []string{
+		"NAME: Foo",
+		"AGE:  1",
+		"",
+		"NAME: Bar",
+		"AGE:  2",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintStream_JSON(t *testing.T) {
+	c := make(chan string, 2)
+	c <- "foo"
+	c <- "bar"
+	close(c)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("json", c, out))
+	assert.JSONEq(t, `["foo", "bar"]`, out.String())
+}
+
+func TestPrintTableStream_Limit(t *testing.T) {
+	c := make(chan streamRow, 3)
+	c <- streamRow{Name: "Foo", Age: 1}
+	c <- streamRow{Name: "Bar", Age: 2}
+	c <- streamRow{Name: "Baz", Age: 3}
+	close(c)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", c, out, Limit(2)))
+	expected := []string{
+		"NAME    AGE",
+		"Foo     1",
+		"Bar     2",
+		"... more rows",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTableStream_LimitUnbounded(t *testing.T) {
+	c := make(chan streamRow)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case c <- streamRow{Name: "Foo", Age: i}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", c, out, Limit(2), StreamLimitMessage("more rows (use --limit 0 to show all)")))
+	expected := []string{
+		"NAME    AGE",
+		"Foo     0",
+		"Foo     1",
+		"more rows (use --limit 0 to show all)",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrint_Iterator(t *testing.T) {
+	seq := func(yield func(streamRow) bool) {
+		for _, r := range []streamRow{{Name: "Foo", Age: 1}, {Name: "Bar", Age: 2}, {Name: "Baz", Age: 3}} {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", seq, out))
+	expected := []string{
+		"NAME    AGE",
+		"Foo     1",
+		"Bar     2",
+		"Baz     3",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	seq2 := func(yield func(int, string) bool) {
+		for i, s := range []string{"foo", "bar"} {
+			if !yield(i, s) {
+				return
+			}
+		}
+	}
+
+	out.Reset()
+	require.NoError(t, PrintWriter("json", seq2, out))
+	assert.JSONEq(t, `["foo", "bar"]`, out.String())
+}
+
+func TestPrint_IteratorStopped(t *testing.T) {
+	stopped := make(chan bool, 1)
+	seq := func(yield func(streamRow) bool) {
+		for i := 0; ; i++ {
+			if !yield(streamRow{Age: i}) {
+				stopped <- true
+				return
+			}
+		}
+	}
+
+	err := PrintWriter("table", seq, new(bytes.Buffer), SortBy("age", false))
+	assert.EqualError(t, err, "cannot sort, group or split a stream")
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("iterator was not stopped")
+	}
+}
+
+func TestPrint_IteratorLimit(t *testing.T) {
+	seq := func(yield func(streamRow) bool) {
+		for i := 0; ; i++ {
+			if !yield(streamRow{Name: "Foo", Age: i}) {
+				return
+			}
+		}
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", seq, out, Limit(2)))
+	expected := []string{
+		"NAME    AGE",
+		"Foo     0",
+		"Foo     1",
+		"... more rows",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("detail", seq, out, Limit(1)))
+	expected = []string{
+		"NAME: Foo",
+		"AGE:  0",
+		"",
+		"... more rows",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintStream_JSONWriteError(t *testing.T) {
+	stopped := make(chan bool, 1)
+	seq := func(yield func(streamRow) bool) {
+		for i := 0; ; i++ {
+			if !yield(streamRow{Name: "Foo", Age: i}) {
+				stopped <- true
+				return
+			}
+		}
+	}
+
+	err := PrintWriter("json", seq, errWriter{})
+	assert.EqualError(t, err, "broken pipe")
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("iterator was not stopped")
+	}
+}
+
+// errWriter is an io.Writer that always fails.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestPrintTableStream_Title(t *testing.T) {
+	c := make(chan streamRow, 1)
+	c <- streamRow{Name: "Foo", Age: 1}
+	close(c)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", c, out, Title("Users")))
+	expected := []string{
+		"Users",
+		"NAME    AGE",
+		"Foo     1",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
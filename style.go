@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// A Style describes how text is rendered on a terminal. It consists of one or
+// more ANSI SGR parameters separated by semicolons (e.g. "1;31" for bold red
+// text). The zero value does not change the text.
+type Style string
+
+// Predefined styles which can be combined via Style.With.
+const (
+	Bold      Style = "1"
+	Faint     Style = "2"
+	Italic    Style = "3"
+	Underline Style = "4"
+	Red       Style = "31"
+	Green     Style = "32"
+	Yellow    Style = "33"
+	Blue      Style = "34"
+	Magenta   Style = "35"
+	Cyan      Style = "36"
+	Gray      Style = "90"
+)
+
+// With returns a new style that combines s with other.
+func (s Style) With(other Style) Style {
+	switch {
+	case s == "":
+		return other
+	case other == "":
+		return s
+	default:
+		return s + ";" + other
+	}
+}
+
+// apply wraps text in the ANSI escape sequences of the style.
+func (s Style) apply(text string) string {
+	if s == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + string(s) + "m" + text + "\x1b[0m"
+}
+
+// ColorMode controls if output is styled using ANSI escape sequences.
+type ColorMode int
+
+// The available color modes.
+const (
+	// ColorAuto enables colors only if the output is written to a terminal
+	// and the NO_COLOR environment variable is not set.
+	ColorAuto ColorMode = iota
+	// ColorAlways enables colors regardless of the output.
+	ColorAlways
+	// ColorNever disables all colors.
+	ColorNever
+)
+
+// enabled returns true if output that is written to w should be styled.
+func (m ColorMode) enabled(w io.Writer) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		_, noColor := os.LookupEnv("NO_COLOR")
+		return !noColor && isTerminal(w)
+	}
+}
+
+// isTerminal returns true if w is a file that refers to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := unwrapWriter(w).(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// terminalWidth returns the width of the terminal that w refers to or 0 if w is
+// not a terminal.
+func terminalWidth(w io.Writer) int {
+	width, _ := terminalSize(w)
+	return width
+}
+
+// terminalHeight returns the height of the terminal that w refers to or 0 if w
+// is not a terminal.
+func terminalHeight(w io.Writer) int {
+	_, height := terminalSize(w)
+	return height
+}
+
+// terminalSize returns the width and height of the terminal that w refers to or
+// zeros if w is not a terminal.
+func terminalSize(w io.Writer) (width, height int) {
+	f, ok := unwrapWriter(w).(*os.File)
+	if !ok {
+		return 0, 0
+	}
+
+	width, height, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0, 0
+	}
+	return width, height
+}
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+
+// stripANSI removes all ANSI escape sequences from s.
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// StripANSIWriter returns a writer that removes all ANSI escape sequences from
+// the data that is written to w. Escape sequences which are split across
+// multiple writes are removed as well. Print uses this writer automatically if
+// colors are disabled so values that contain escape sequences (e.g. because
+// they implement TableCell) do not leak them into piped or logged output.
+func StripANSIWriter(w io.Writer) io.Writer {
+	if _, ok := w.(*ansiStripper); ok {
+		return w
+	}
+	return &ansiStripper{w: w}
+}
+
+// ansiStripper is the io.Writer that is returned by StripANSIWriter.
+type ansiStripper struct {
+	w io.Writer
+
+	// pending contains the beginning of an escape sequence that was not
+	// completed by the last write.
+	pending []byte
+}
+
+// Write implements the io.Writer interface.
+func (s *ansiStripper) Write(p []byte) (int, error) {
+	buf := p
+	if len(s.pending) > 0 {
+		buf = append(s.pending, p...)
+		s.pending = nil
+	}
+
+	if i := bytes.LastIndexByte(buf, '\x1b'); i >= 0 && incompleteEscape(buf[i:]) {
+		s.pending = append([]byte(nil), buf[i:]...)
+		buf = buf[:i]
+	}
+
+	if bytes.IndexByte(buf, '\x1b') >= 0 {
+		buf = ansiEscape.ReplaceAll(buf, nil)
+	}
+
+	if _, err := s.w.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// incompleteEscape returns true if b is the beginning of an escape sequence
+// that may be completed by the next write.
+func incompleteEscape(b []byte) bool {
+	if len(b) == 1 {
+		return true
+	}
+	if b[1] != '[' {
+		return false
+	}
+	for _, c := range b[2:] {
+		if (c < '0' || c > '9') && c != ';' {
+			return false
+		}
+	}
+	return true
+}
+
+// unwrapWriter returns the writer that is wrapped by w if it was returned by
+// StripANSIWriter.
+func unwrapWriter(w io.Writer) io.Writer {
+	if s, ok := w.(*ansiStripper); ok {
+		return s.w
+	}
+	return w
+}
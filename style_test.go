@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStyle_With(t *testing.T) {
+	assert.Equal(t, Style("1;31"), Bold.With(Red))
+	assert.Equal(t, Red, Style("").With(Red))
+	assert.Equal(t, Bold, Bold.With(""))
+}
+
+func TestStyle_Apply(t *testing.T) {
+	assert.Equal(t, "\x1b[1;31mtest\x1b[0m", Bold.With(Red).apply("test"))
+	assert.Equal(t, "test", Style("").apply("test"))
+	assert.Equal(t, "", Bold.apply(""))
+}
+
+func TestColorMode(t *testing.T) {
+	w := new(bytes.Buffer)
+	assert.True(t, ColorAlways.enabled(w))
+	assert.False(t, ColorNever.enabled(w))
+	assert.False(t, ColorAuto.enabled(w))
+}
+
+func TestStripANSI(t *testing.T) {
+	assert.Equal(t, "test", stripANSI("\x1b[1;31mtest\x1b[0m"))
+	assert.Equal(t, "a b", stripANSI("a\x1b[2K b"))
+	assert.Equal(t, 4, textWidth("\x1b[1mtäst\x1b[0m"))
+}
+
+func TestStripANSIWriter(t *testing.T) {
+	cases := map[string]struct {
+		writes   []string
+		expected string
+	}{
+		"plain": {
+			writes:   []string{"hello ", "world\n"},
+			expected: "hello world\n",
+		},
+		"single write": {
+			writes:   []string{"\x1b[1;31mred\x1b[0m\n"},
+			expected: "red\n",
+		},
+		"split sequence": {
+			writes:   []string{"\x1b[1", ";31mred\x1b", "[0m\n"},
+			expected: "red\n",
+		},
+		"other escape": {
+			writes:   []string{"a\x1bb"},
+			expected: "a\x1bb",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			w := StripANSIWriter(out)
+			for _, s := range c.writes {
+				n, err := w.Write([]byte(s))
+				assert.NoError(t, err)
+				assert.Equal(t, len(s), n)
+			}
+			assert.Equal(t, c.expected, out.String())
+		})
+	}
+}
+
+func TestPrintWriter_StripANSI(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, PrintWriter("raw", "\x1b[32mok\x1b[0m", out))
+	assert.Equal(t, "ok\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, PrintWriter("raw", "\x1b[32mok\x1b[0m", out, Color(ColorAlways)))
+	assert.Equal(t, "\x1b[32mok\x1b[0m\n", out.String())
+}
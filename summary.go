@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"io"
+	"math"
+	"reflect"
+)
+
+// summaryMode controls if and how the summary of a table is printed.
+type summaryMode int
+
+const (
+	summaryNone summaryMode = iota
+	summaryAppend
+	summaryOnly
+)
+
+// summaryStats are the names of the rows of a summary table.
+var summaryStats = []string{"count", "min", "max", "mean"}
+
+// writeSummary writes a table with the statistics of all numeric fields of the
+// given rows. Nothing is written if there are no numeric fields.
+func writeSummary(w io.Writer, fields []field, rows []reflect.Value, opts *options) error {
+	var numeric []field
+	for _, f := range fields {
+		if isSummaryField(f) {
+			numeric = append(numeric, f)
+		}
+	}
+
+	if len(numeric) == 0 {
+		return nil
+	}
+
+	layout := &tableLayout{
+		header:   []string{"SUMMARY"},
+		records:  make([][]string, len(summaryStats)),
+		border:   opts.border,
+		maxWidth: opts.tableWidth(w),
+	}
+
+	for i, stat := range summaryStats {
+		layout.records[i] = []string{stat}
+	}
+
+	for _, f := range numeric {
+		layout.header = append(layout.header, f.Column())
+		for i, value := range summarize(f, rows, opts) {
+			layout.records[i] = append(layout.records[i], value)
+		}
+	}
+
+	layout.parents = make([]string, len(layout.header))
+	layout.wraps = make([]int, len(layout.header))
+	if opts.useColor(w) {
+		layout.headerStyle = opts.headerStyle
+	}
+
+	return layout.write(w)
+}
+
+// isSummaryField returns true if the statistics of f can be computed. These
+// are all numeric fields which are not printed via custom methods except for
+// durations.
+func isSummaryField(f field) bool {
+	typ := baseType(f.Type)
+	if typ == durationType {
+		return true
+	}
+
+	ptr := reflect.PtrTo(typ)
+	return isNumber(typ.Kind()) && !f.Tag.Redact &&
+		!ptr.Implements(tableCellType) && !ptr.Implements(stringerType)
+}
+
+// summarize returns the formatted statistics of f in the order of
+// summaryStats. The minimum, maximum and mean are formatted like the cells of
+// the field. Statistics of fields without any values are printed as
+// placeholder.
+func summarize(f field, rows []reflect.Value, opts *options) []string {
+	var (
+		count    int
+		sum      float64
+		min, max reflect.Value
+	)
+
+	for _, row := range rows {
+		v := indirect(f.value(row))
+		if isEmptyValue(v) {
+			continue
+		}
+
+		n := toFloat(v)
+		if count == 0 || n < toFloat(min) {
+			min = v
+		}
+		if count == 0 || n > toFloat(max) {
+			max = v
+		}
+		sum += n
+		count++
+	}
+
+	stats := []string{formatNumber(reflect.ValueOf(count), tableTag{}, opts)}
+	if count == 0 {
+		return append(stats, opts.placeholder, opts.placeholder, opts.placeholder)
+	}
+
+	// The mean of integers is only rounded if the field is printed as a
+	// duration or byte size.
+	typ := baseType(f.Type)
+	mean := reflect.ValueOf(sum / float64(count))
+	if !isFloat(typ.Kind()) && (f.Tag.Bytes || typ == durationType) {
+		mean = reflect.ValueOf(math.Round(mean.Float())).Convert(typ)
+	}
+
+	return append(stats, formatCell(min, f, opts), formatCell(max, f, opts), formatCell(mean, f, opts))
+}
+
+// toFloat converts the numeric value v to a float64.
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isInt(v.Kind()):
+		return float64(v.Int())
+	case isUint(v.Kind()):
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintTable_Summary(t *testing.T) {
+	type host struct {
+		Name    string
+		CPUs    int
+		Memory  uint64 `table:",bytes"`
+		Load    *float64
+		Uptime  time.Duration
+		Status  testStatus
+		Comment string
+	}
+
+	load := 0.5
+	rows := []host{
+		{Name: "a", CPUs: 2, Memory: 2 << 30, Load: &load, Uptime: time.Hour},
+		{Name: "b", CPUs: 8, Memory: 8 << 30, Uptime: 3 * time.Hour},
+		{Name: "c", CPUs: 4, Memory: 4 << 30, Uptime: 2 * time.Hour},
+		{Name: "d", CPUs: 1, Memory: 1 << 30, Uptime: 6 * time.Hour},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, SummaryOnly()))
+	expected := []string{
+		"SUMMARY  CPUS    MEMORY   LOAD    UPTIME",
+		"count    4       4        1       4",
+		"min      1       1 GiB    0.5     1h",
+		"max      8       8 GiB    0.5     6h",
+		"mean     3.75    3.8 GiB  0.5     3h",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Summary(), Columns("name", "cpus"), Limit(1)))
+	expected = []string{
+		"NAME    CPUS",
+		"a       2",
+		"... 3 more rows",
+		"",
+		"SUMMARY  CPUS",
+		"count    4",
+		"min      1",
+		"max      8",
+		"mean     3.75",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", []host{}, out, SummaryOnly(), Columns("cpus")))
+	expected = []string{
+		"SUMMARY  CPUS",
+		"count    0",
+		"min      -",
+		"max      -",
+		"mean     -",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
@@ -0,0 +1,1514 @@
+package cli
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+func printTable(v interface{}, w io.Writer, opts *options) error {
+	if header, rows, ok := marshalTable(v); ok {
+		return printTableMarshaler(header, rows, w, opts)
+	}
+
+	val := reflect.ValueOf(v)
+	t, rows, isArray := tableRows(val)
+	if len(rows) == 0 && opts.noResults != "" {
+		return writeNoResults(w, opts)
+	}
+	if t.Kind() != reflect.Struct {
+		// Values that cannot be printed as a table are printed like the
+		// "raw" encoding does. Slices and arrays are printed with one
+		// element per line.
+		if err := writeTitle(w, opts.useColor(w), opts); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			_, err := fmt.Fprintln(w, row)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	fields, err := tableFields(t, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := sortTable(fields, rows, opts); err != nil {
+		return err
+	}
+
+	if opts.splitBy != "" {
+		return printSplitTables(w, fields, rows, isArray, opts)
+	}
+
+	return writeTable(w, fields, rows, isArray, opts)
+}
+
+// printSplitTables partitions the rows by the value of the column of the
+// SplitBy option and prints one table per partition. The title of each table
+// contains the value of the column.
+func printSplitTables(w io.Writer, fields []field, rows []reflect.Value, isArray bool, opts *options) error {
+	split, ok := findField(fields, opts.splitBy)
+	if !ok {
+		return fmt.Errorf("cannot split by unknown column %q", opts.splitBy)
+	}
+
+	if err := writeTitle(w, opts.useColor(w), opts); err != nil {
+		return err
+	}
+
+	tables, titles := splitRows(rows, split, opts)
+	for n, table := range tables {
+		if n > 0 {
+			// Tables are separated by an empty line.
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+
+		o := *opts
+		o.title = titles[n]
+		if err := writeTable(w, fields, table, isArray, &o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitRows partitions the rows by the value of field f. Partitions are
+// ordered by their first appearance. It returns the rows and the title of each
+// partition.
+func splitRows(rows []reflect.Value, f field, opts *options) ([][]reflect.Value, []string) {
+	rows, sections := groupRows(rows, f, opts)
+	starts := make([]int, 0, len(sections))
+	for i := range sections {
+		starts = append(starts, i)
+	}
+	sort.Ints(starts)
+
+	tables := make([][]reflect.Value, len(starts))
+	titles := make([]string, len(starts))
+	for n, start := range starts {
+		end := len(rows)
+		if n+1 < len(starts) {
+			end = starts[n+1]
+		}
+		tables[n], titles[n] = rows[start:end], sections[start]
+	}
+	return tables, titles
+}
+
+// writeTable writes the rows as a single table.
+func writeTable(w io.Writer, fields []field, rows []reflect.Value, isArray bool, opts *options) error {
+	var group field
+	if opts.groupBy != "" {
+		var ok bool
+		group, ok = findField(fields, opts.groupBy)
+		if !ok {
+			return fmt.Errorf("cannot group by unknown column %q", opts.groupBy)
+		}
+	}
+
+	footer, err := tableFooter(fields, rows, isArray, opts)
+	if err != nil {
+		return err
+	}
+
+	fields, err = selectColumns(fields, rows, opts)
+	if err != nil {
+		return err
+	}
+
+	var sections map[int]string
+	if opts.groupBy != "" {
+		rows, sections = groupRows(rows, group, opts)
+		fields = removeField(fields, group)
+	}
+
+	// The column of the SplitBy option is already part of the title.
+	if split, ok := findField(fields, opts.splitBy); ok && opts.splitBy != "" {
+		fields = removeField(fields, split)
+	}
+
+	if err := writeTitle(w, opts.useColor(w), opts); err != nil {
+		return err
+	}
+
+	if opts.summary == summaryOnly {
+		return writeSummary(w, fields, rows, opts)
+	}
+
+	limited, more := limitRows(rows, opts)
+	_, layout := fitTable(fields, func(fields []field) *tableLayout {
+		return newTableLayout(w, fields, limited, footer, sections, opts)
+	})
+	if err := layout.write(w); err != nil {
+		return err
+	}
+
+	if err := writeLimitMessage(w, more, opts); err != nil {
+		return err
+	}
+
+	if opts.summary == summaryAppend && isArray {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+		return writeSummary(w, fields, rows, opts)
+	}
+
+	return nil
+}
+
+// writeTitle writes the title of the Title option on its own line.
+func writeTitle(w io.Writer, color bool, opts *options) error {
+	if opts.title == "" {
+		return nil
+	}
+
+	title := opts.title
+	if color {
+		title = opts.headerStyle.apply(title)
+	}
+	_, err := io.WriteString(w, title+"\n")
+	return err
+}
+
+// fitTable removes the fields with the highest "priority" tag option from the
+// table until it fits into the maximum width of the layout (see MaxWidth). If
+// multiple fields have the same priority, the last one is removed first. It
+// returns the remaining fields and their layout.
+func fitTable(fields []field, newLayout func([]field) *tableLayout) ([]field, *tableLayout) {
+	layout := newLayout(fields)
+	for layout.maxWidth > 0 && layout.width() > layout.maxWidth {
+		drop := -1
+		for i, f := range fields {
+			if f.Tag.Priority > 0 && (drop < 0 || f.Tag.Priority >= fields[drop].Tag.Priority) {
+				drop = i
+			}
+		}
+		if drop < 0 {
+			break
+		}
+
+		fields = append(fields[:drop:drop], fields[drop+1:]...)
+		layout = newLayout(fields)
+	}
+	return fields, layout
+}
+
+// mergeRepeated clears all cells of fields with the "merge" tag option whose
+// value equals the cell of the previous record. A cell is only cleared if the
+// cells of all merged fields to its left have been cleared as well, so merged
+// columns form a hierarchy. Merging starts again with each section.
+func mergeRepeated(records [][]string, fields []field, sections map[int]string) {
+	for i := len(records) - 1; i > 0; i-- {
+		if _, ok := sections[i]; ok {
+			continue
+		}
+
+		for j, f := range fields {
+			if !f.Tag.Merge {
+				continue
+			}
+			if records[i][j] != records[i-1][j] {
+				break
+			}
+			records[i][j] = ""
+		}
+	}
+}
+
+// limitRows returns the rows that should be printed according to the Limit
+// option and the number of rows that were omitted.
+func limitRows(rows []reflect.Value, opts *options) ([]reflect.Value, int) {
+	if opts.limit <= 0 || len(rows) <= opts.limit {
+		return rows, 0
+	}
+	return rows[:opts.limit], len(rows) - opts.limit
+}
+
+// writeNoResults writes the title and the message of the NoResults option.
+func writeNoResults(w io.Writer, opts *options) error {
+	if err := writeTitle(w, opts.useColor(w), opts); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, opts.noResults+"\n")
+	return err
+}
+
+// writeLimitMessage writes the message of the Limit option if more than zero
+// rows have been omitted. If the number of omitted rows is unknown (i.e. more
+// is negative), the message of the StreamLimitMessage option is written.
+func writeLimitMessage(w io.Writer, more int, opts *options) error {
+	switch {
+	case more == 0:
+		return nil
+	case more < 0:
+		_, err := io.WriteString(w, opts.streamLimitMessage+"\n")
+		return err
+	}
+	_, err := fmt.Fprintf(w, opts.limitMessage+"\n", more)
+	return err
+}
+
+// newTableLayout formats the given rows and returns the layout of the table.
+// The footer contains the footer values by column name and sections contains
+// the section titles by row index. Both may be nil.
+func newTableLayout(w io.Writer, fields []field, rows []reflect.Value, footer map[string]string, sections map[int]string, opts *options) *tableLayout {
+	layout := &tableLayout{
+		header:    make([]string, len(fields)),
+		parents:   make([]string, len(fields)),
+		wraps:     make([]int, len(fields)),
+		minWidths: make([]int, len(fields)),
+		records:   make([][]string, len(rows)),
+		sections:  sections,
+		border:    opts.border,
+		maxWidth:  opts.tableWidth(w),
+	}
+
+	for i, f := range fields {
+		layout.header[i] = f.Column()
+		layout.wraps[i] = f.Tag.Wrap
+		layout.minWidths[i] = f.Tag.MinWidth
+		if opts.spanningHeaders {
+			layout.header[i] = f.Name
+			layout.parents[i] = f.Parent
+		}
+	}
+
+	// The cells of all records share a single backing array to reduce the
+	// number of allocations for large tables.
+	cells := make([]string, len(rows)*len(fields))
+	for i, row := range rows {
+		layout.records[i] = cells[i*len(fields) : (i+1)*len(fields) : (i+1)*len(fields)]
+		for j, f := range fields {
+			layout.records[i][j] = escapeCell(formatCell(f.value(row), f, opts), f, opts)
+		}
+	}
+
+	mergeRepeated(layout.records, fields, sections)
+
+	// The footer may contain values of columns that are not selected and is
+	// only printed if at least one of the printed columns has a footer value.
+	record := make([]string, len(fields))
+	for i, f := range fields {
+		if value, ok := footer[f.Column()]; ok {
+			record[i] = value
+			layout.footer = true
+		}
+	}
+	if layout.footer {
+		layout.records = append(layout.records, record)
+	}
+
+	if opts.rowNumbers {
+		layout.header = append([]string{"#"}, layout.header...)
+		layout.parents = append([]string{""}, layout.parents...)
+		layout.wraps = append([]int{0}, layout.wraps...)
+		layout.minWidths = append([]int{0}, layout.minWidths...)
+		for i := range layout.records {
+			var n string
+			if i < len(rows) {
+				n = strconv.Itoa(i + 1)
+			}
+			layout.records[i] = append([]string{n}, layout.records[i]...)
+		}
+	}
+
+	if opts.useColor(w) {
+		layout.headerStyle = opts.headerStyle
+		if opts.cellStyle != nil || opts.trueStyle != "" || opts.falseStyle != "" || len(opts.highlights) > 0 {
+			layout.styles = cellStyles(fields, rows, layout.header, opts)
+		}
+	}
+
+	return layout
+}
+
+// cellStyles returns the styles of all cells of the given rows. Boolean cells
+// that have no style according to the CellStyle option are styled as set via
+// the BooleanStyles option. The styles of rows that match the HighlightIf
+// option are combined with the styles of their cells.
+func cellStyles(fields []field, rows []reflect.Value, header []string, opts *options) [][]Style {
+	// The header may start with the row number column which has no field.
+	offset := len(header) - len(fields)
+
+	styles := make([][]Style, len(rows))
+	for i, row := range rows {
+		var highlight Style
+		for _, h := range opts.highlights {
+			if h.match(row.Interface()) {
+				highlight = highlight.With(h.style)
+			}
+		}
+
+		styles[i] = make([]Style, len(header))
+		for j, column := range header {
+			var style Style
+			if opts.cellStyle != nil {
+				style = opts.cellStyle(row.Interface(), column)
+			}
+
+			if style == "" && j >= offset {
+				if v := indirect(fields[j-offset].value(row)); v.Kind() == reflect.Bool && !isEmptyValue(v) {
+					style = opts.falseStyle
+					if v.Bool() {
+						style = opts.trueStyle
+					}
+				}
+			}
+
+			styles[i][j] = highlight.With(style)
+		}
+	}
+	return styles
+}
+
+// tableRows returns the element type of val and its elements if val is a slice
+// or an array. Otherwise the type of val and val itself is returned. Pointers
+// are dereferenced. Nil pointers and nil are returned without rows.
+//
+// If the elements are interfaces and all non-nil elements have the same
+// dynamic type (or pointers to it), that type is returned together with the
+// dynamic values of the elements. Otherwise the interface type is returned so
+// each element is printed on its own line.
+func tableRows(val reflect.Value) (t reflect.Type, rows []reflect.Value, isArray bool) {
+	if !val.IsValid() {
+		return emptyInterfaceType, nil, true
+	}
+
+	t = val.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		switch {
+		case !val.IsNil():
+			val = val.Elem()
+		case t.Kind() == reflect.Map || t.Kind() == reflect.Slice:
+			val = reflect.Zero(t)
+		case t.Kind() == reflect.Array:
+			return baseType(t.Elem()), nil, true
+		default:
+			// Nil pointers have no rows rather than a made up zero value.
+			return t, nil, false
+		}
+	}
+
+	if t.Kind() == reflect.Map {
+		t, rows = mapRows(val)
+		return t, rows, true
+	}
+
+	if t.Kind() != reflect.Array && t.Kind() != reflect.Slice {
+		return t, []reflect.Value{val}, false
+	}
+
+	rows = make([]reflect.Value, val.Len())
+	for i := range rows {
+		rows[i] = val.Index(i)
+	}
+
+	if t.Elem().Kind() == reflect.Interface {
+		t, rows = dynamicRows(t.Elem(), rows)
+		return t, rows, true
+	}
+
+	return baseType(t.Elem()), rows, true
+}
+
+// mapRows returns the entries of the map val as rows of a struct type that is
+// created at runtime. The first column of the rows contains the keys of the map.
+// If the values of the map are structs (or pointers to structs), their fields
+// are the other columns. Otherwise the values are printed in a single column.
+// Rows are sorted by key.
+func mapRows(val reflect.Value) (reflect.Type, []reflect.Value) {
+	keyField := reflect.StructField{Name: "Key", Type: val.Type().Key(), Tag: `table:"KEY"`}
+	structFields := []reflect.StructField{keyField}
+
+	// index contains the index of each struct field of the values or is nil
+	// if the values are printed in a single column. If the values are
+	// pointers, the columns are pointers as well so nil values are printed as
+	// placeholders.
+	var index []int
+	elem := val.Type().Elem()
+	base, isPtr := elem, elem.Kind() == reflect.Ptr
+	if isPtr {
+		base = elem.Elem()
+	}
+	if base.Kind() == reflect.Struct {
+		if _, ok := base.FieldByName(keyField.Name); !ok {
+			index = []int{}
+			for i := 0; i < base.NumField(); i++ {
+				f := base.Field(i)
+				if f.PkgPath != "" {
+					continue
+				}
+				if isPtr {
+					f.Type = reflect.PtrTo(f.Type)
+				}
+				structFields = append(structFields, reflect.StructField{Name: f.Name, Type: f.Type, Tag: f.Tag})
+				index = append(index, i)
+			}
+		}
+	}
+
+	if index == nil {
+		structFields = append(structFields, reflect.StructField{Name: "Value", Type: elem, Tag: `table:"VALUE"`})
+	}
+
+	t := reflect.StructOf(structFields)
+	keys := val.MapKeys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compareValues(keys[i], keys[j]) < 0
+	})
+
+	rows := make([]reflect.Value, len(keys))
+	for i, key := range keys {
+		row := reflect.New(t).Elem()
+		row.Field(0).Set(key)
+
+		value := val.MapIndex(key)
+		switch {
+		case index == nil:
+			row.Field(1).Set(value)
+		case !isPtr:
+			for j, n := range index {
+				row.Field(j + 1).Set(value.Field(n))
+			}
+		case !value.IsNil():
+			value = value.Elem()
+			for j, n := range index {
+				row.Field(j + 1).Set(value.Field(n).Addr())
+			}
+		}
+		rows[i] = row
+	}
+
+	return t, rows
+}
+
+// dynamicRows returns the common dynamic type of the interface values rows
+// and their dynamic values. Nil elements are replaced with nil pointers of the
+// common type. If the non-nil elements have different dynamic types, t and the
+// original rows are returned.
+func dynamicRows(t reflect.Type, rows []reflect.Value) (reflect.Type, []reflect.Value) {
+	var typ reflect.Type
+	for _, row := range rows {
+		if row.IsNil() {
+			continue
+		}
+
+		switch base := baseType(row.Elem().Type()); {
+		case typ == nil:
+			typ = base
+		case typ != base:
+			return t, rows
+		}
+	}
+
+	if typ == nil {
+		return t, rows
+	}
+
+	values := make([]reflect.Value, len(rows))
+	for i, row := range rows {
+		if row.IsNil() {
+			values[i] = reflect.Zero(reflect.PtrTo(typ))
+		} else {
+			values[i] = row.Elem()
+		}
+	}
+	return typ, values
+}
+
+// sortTable sorts the rows according to the SortBy option.
+func sortTable(fields []field, rows []reflect.Value, opts *options) error {
+	if opts.sortBy == "" {
+		return nil
+	}
+
+	f, ok := findField(fields, opts.sortBy)
+	if !ok {
+		return fmt.Errorf("cannot sort by unknown column %q", opts.sortBy)
+	}
+
+	sortRows(rows, f, opts.sortDesc)
+	return nil
+}
+
+// selectColumns returns the fields that should be printed according to the
+// Columns and HideEmptyColumns options. The MinColumnWidth and MergeRepeated
+// options are applied to the returned fields.
+func selectColumns(fields []field, rows []reflect.Value, opts *options) ([]field, error) {
+	if len(opts.minWidths) > 0 {
+		fields = append([]field(nil), fields...)
+		for name, width := range opts.minWidths {
+			i := fieldIndex(fields, name)
+			if i < 0 {
+				return nil, fmt.Errorf("unknown column %q", name)
+			}
+			fields[i].Tag.MinWidth = width
+		}
+	}
+
+	if opts.merge != nil {
+		fields = append([]field(nil), fields...)
+		for i := range fields {
+			fields[i].Tag.Merge = fields[i].Tag.Merge || len(opts.merge) == 0
+		}
+		for _, name := range opts.merge {
+			i := fieldIndex(fields, name)
+			if i < 0 {
+				return nil, fmt.Errorf("unknown column %q", name)
+			}
+			fields[i].Tag.Merge = true
+		}
+	}
+
+	if len(opts.columns) > 0 {
+		selected := make([]field, len(opts.columns))
+		for i, name := range opts.columns {
+			f, ok := findField(fields, name)
+			if !ok {
+				return nil, fmt.Errorf("unknown column %q", name)
+			}
+			selected[i] = f
+		}
+		fields = selected
+	}
+
+	if opts.hideEmpty && len(rows) > 0 {
+		fields = nonEmptyFields(fields, rows)
+	}
+
+	return fields, nil
+}
+
+// A field is a single column of a table.
+type field struct {
+	// Name is the name of the column.
+	Name string
+
+	// Parent is the column name of the struct that contains this field if
+	// the field belongs to a flattened struct.
+	Parent string
+
+	// FieldName is the name of the struct field. Fields of flattened structs
+	// are prefixed with the name of their parent field (e.g. "Owner.Name").
+	FieldName string
+
+	// Index is the index sequence of the struct field (see
+	// reflect.Value.FieldByIndex).
+	Index []int
+
+	Type reflect.Type
+	Tag  tableTag
+}
+
+// Column returns the full name of the column. Columns of flattened structs are
+// prefixed with the name of their parent column (e.g. "OWNER.NAME").
+func (f field) Column() string {
+	if f.Parent == "" {
+		return f.Name
+	}
+	return f.Parent + "." + f.Name
+}
+
+// value returns the value of the field in the given row. If the field belongs
+// to a flattened struct that is referenced via a nil pointer, a nil pointer to
+// the type of the field is returned.
+func (f field) value(row reflect.Value) reflect.Value {
+	v := row
+	for _, i := range f.Index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Zero(reflect.PtrTo(f.Type))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+// fieldCacheKey is the key of the fieldCache.
+type fieldCacheKey struct {
+	Type       reflect.Type
+	HeaderCase HeaderCase
+	MaxDepth   int
+}
+
+// fieldCache contains the result of parseTableFields by type and header case
+// so the fields of a type do not have to be parsed each time it is printed.
+var fieldCache sync.Map // map[fieldCacheKey][]field
+
+// tableFields returns the fields of the struct type t that should be printed
+// as table columns. The result is cached and callers receive a copy they are
+// allowed to modify.
+func tableFields(t reflect.Type, opts *options) ([]field, error) {
+	key := fieldCacheKey{Type: t, HeaderCase: opts.headerCase, MaxDepth: opts.maxDepth}
+	if fields, ok := fieldCache.Load(key); ok {
+		return append([]field(nil), fields.([]field)...), nil
+	}
+
+	fields, err := parseTableFields(t, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldCache.Store(key, fields)
+	return append([]field(nil), fields...), nil
+}
+
+// parseTableFields parses the fields and tags of the struct type t. Parents
+// contains the types of the structs that t is flattened into.
+func parseTableFields(t reflect.Type, opts *options, parents []reflect.Type) ([]field, error) {
+	var fields []field
+
+	// orders contains the value of the "order" tag option of each field. The
+	// fields of a flattened struct share the order of the struct field.
+	var orders []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, err := parseTableTag(f.Tag.Get("table"))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", f.Name, err)
+		}
+
+		if tag.Name == "-" {
+			continue
+		}
+
+		name := opts.headerCase.apply(f.Name)
+		if tag.Name != "" {
+			name = tag.Name
+		}
+
+		if tag.Flatten {
+			child := baseType(f.Type)
+			if child.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("field %s: flatten option requires a struct field", f.Name)
+			}
+
+			parents := append(parents[:len(parents):len(parents)], t)
+			for _, p := range parents {
+				if p == child {
+					return nil, fmt.Errorf("field %s: cannot flatten recursive type %v", f.Name, child)
+				}
+			}
+
+			if len(parents) > opts.maxDepth {
+				return nil, fmt.Errorf("field %s: flatten exceeds the maximum depth of %d", f.Name, opts.maxDepth)
+			}
+
+			children, err := parseTableFields(child, opts, parents)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", f.Name, err)
+			}
+
+			for _, c := range children {
+				if c.Parent == "" {
+					c.Parent = name
+				} else {
+					c.Parent = name + "." + c.Parent
+				}
+				c.FieldName = f.Name + "." + c.FieldName
+				c.Index = append([]int{i}, c.Index...)
+				fields = append(fields, c)
+				orders = append(orders, tag.Order)
+			}
+			continue
+		}
+
+		// Formatting options also apply to the elements of slices, arrays
+		// and maps.
+		typ := baseType(f.Type)
+		elem := typ
+		switch typ.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			elem = baseType(typ.Elem())
+		}
+		if tag.Sum && !isNumber(typ.Kind()) {
+			return nil, fmt.Errorf("field %s: sum option requires a numeric field", f.Name)
+		}
+
+		if (tag.Format != "" || tag.Since) && elem != timeType {
+			return nil, fmt.Errorf("field %s: format and since options require a time.Time field", f.Name)
+		}
+
+		if tag.Precision != 0 && elem != durationType {
+			return nil, fmt.Errorf("field %s: precision option requires a time.Duration field", f.Name)
+		}
+
+		if tag.Bytes && !isNumber(elem.Kind()) {
+			return nil, fmt.Errorf("field %s: bytes option requires a numeric field", f.Name)
+		}
+
+		if tag.HasDecimals && !isFloat(elem.Kind()) {
+			return nil, fmt.Errorf("field %s: decimals option requires a floating point field", f.Name)
+		}
+
+		if tag.True != "" && elem.Kind() != reflect.Bool {
+			return nil, fmt.Errorf("field %s: bool option requires a boolean field", f.Name)
+		}
+
+		fields = append(fields, field{
+			Name:      name,
+			FieldName: f.Name,
+			Index:     []int{i},
+			Type:      f.Type,
+			Tag:       tag,
+		})
+		orders = append(orders, tag.Order)
+	}
+
+	return orderFields(fields, orders), nil
+}
+
+// orderFields sorts the fields by the values of their "order" tag option.
+// Fields with an order are placed before all other fields, which keep the
+// order in which they are declared.
+func orderFields(fields []field, orders []int) []field {
+	index := make([]int, len(fields))
+	for i := range index {
+		index[i] = i
+	}
+
+	sort.SliceStable(index, func(i, j int) bool {
+		a, b := orders[index[i]], orders[index[j]]
+		switch {
+		case a == 0:
+			return false
+		case b == 0:
+			return true
+		default:
+			return a < b
+		}
+	})
+
+	sorted := make([]field, len(fields))
+	for i, n := range index {
+		sorted[i] = fields[n]
+	}
+	return sorted
+}
+
+// tableFooter returns the values of the footer of the table by column name. The
+// footer contains the aggregates of all fields with a "sum" or
+// "count" tag option as well as all values set via the Footer option.
+// Aggregates are only computed for slices and arrays.
+func tableFooter(fields []field, rows []reflect.Value, isArray bool, opts *options) (map[string]string, error) {
+	footer := map[string]string{}
+	for _, f := range fields {
+		switch {
+		case !isArray:
+			continue
+		case f.Tag.Sum:
+			sum := reflect.New(baseType(f.Type)).Elem()
+			for _, row := range rows {
+				v := indirect(f.value(row))
+				switch {
+				case isEmptyValue(v):
+					continue
+				case isInt(v.Kind()):
+					sum.SetInt(sum.Int() + v.Int())
+				case isUint(v.Kind()):
+					sum.SetUint(sum.Uint() + v.Uint())
+				default:
+					sum.SetFloat(sum.Float() + v.Float())
+				}
+			}
+			footer[f.Column()] = formatCell(sum, f, opts)
+		case f.Tag.Count:
+			var n int
+			for _, row := range rows {
+				if !f.value(row).IsZero() {
+					n++
+				}
+			}
+			footer[f.Column()] = formatNumber(reflect.ValueOf(n), f.Tag, opts)
+		}
+	}
+
+	for name, value := range opts.footer {
+		f, ok := findField(fields, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown footer column %q", name)
+		}
+		footer[f.Column()] = value
+	}
+
+	return footer, nil
+}
+
+func isNumber(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || isFloat(k)
+}
+
+func isInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUint(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// findField returns the field whose column name or struct field name matches
+// the given name case insensitively.
+func findField(fields []field, name string) (field, bool) {
+	if i := fieldIndex(fields, name); i >= 0 {
+		return fields[i], true
+	}
+	return field{}, false
+}
+
+// fieldIndex returns the index of the field with the given name like
+// findField does or -1 if there is no such field.
+func fieldIndex(fields []field, name string) int {
+	for i, f := range fields {
+		if strings.EqualFold(f.Column(), name) || strings.EqualFold(f.FieldName, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// groupRows reorders the rows so that all rows with the same value of field f
+// are next to each other. Groups are ordered by their first appearance and the
+// rows within a group keep their order. The returned map contains the section
+// title of each group by the index of its first row.
+func groupRows(rows []reflect.Value, f field, opts *options) ([]reflect.Value, map[int]string) {
+	var keys []string
+	groups := map[string][]reflect.Value{}
+	for _, row := range rows {
+		key := formatCell(f.value(row), f, opts)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	result := make([]reflect.Value, 0, len(rows))
+	sections := make(map[int]string, len(keys))
+	for _, key := range keys {
+		sections[len(result)] = f.Column() + ": " + key
+		result = append(result, groups[key]...)
+	}
+
+	return result, sections
+}
+
+// removeField returns all fields except f.
+func removeField(fields []field, f field) []field {
+	result := make([]field, 0, len(fields))
+	for _, ff := range fields {
+		if ff.FieldName != f.FieldName {
+			result = append(result, ff)
+		}
+	}
+	return result
+}
+
+// nonEmptyFields returns all fields which have a non-zero value in at least one
+// of the given rows.
+func nonEmptyFields(fields []field, rows []reflect.Value) []field {
+	var result []field
+	for _, f := range fields {
+		for _, row := range rows {
+			if !f.value(row).IsZero() {
+				result = append(result, f)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// sortRows sorts the given struct values by the given field. The sort is stable
+// so rows with equal values keep their original order.
+func sortRows(rows []reflect.Value, f field, desc bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := f.value(rows[i]), f.value(rows[j])
+		if desc {
+			return compareValues(b, a) < 0
+		}
+		return compareValues(a, b) < 0
+	})
+}
+
+// compareValues compares a and b and returns -1, 0 or +1. Pointers are
+// dereferenced and nil values are sorted first. Numbers are compared by their
+// value. All other values, including values of different kinds, are compared
+// via their string representation unless both strings can be parsed as
+// numbers.
+func compareValues(a, b reflect.Value) int {
+	a, b = indirect(a), indirect(b)
+	switch aNil, bNil := isNil(a), isNil(b); {
+	case aNil && bNil:
+		return 0
+	case aNil:
+		return -1
+	case bNil:
+		return +1
+	}
+
+	if a.Kind() != b.Kind() {
+		return compareStrings(a, b)
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, y := a.Int(), b.Int()
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return +1
+		}
+		return 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, y := a.Uint(), b.Uint()
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return +1
+		}
+		return 0
+	case reflect.Float32, reflect.Float64:
+		return compareFloats(a.Float(), b.Float())
+	case reflect.Bool:
+		x, y := a.Bool(), b.Bool()
+		switch {
+		case !x && y:
+			return -1
+		case x && !y:
+			return +1
+		}
+		return 0
+	}
+
+	return compareStrings(a, b)
+}
+
+// compareStrings compares the string representations of a and b. The strings
+// are compared as numbers if both can be parsed as numbers.
+func compareStrings(a, b reflect.Value) int {
+	sa, sb := fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface())
+	fa, errA := strconv.ParseFloat(sa, 64)
+	fb, errB := strconv.ParseFloat(sb, 64)
+	if errA == nil && errB == nil {
+		return compareFloats(fa, fb)
+	}
+
+	return strings.Compare(sa, sb)
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return +1
+	default:
+		return 0
+	}
+}
+
+// tableTag contains the parsed contents of a "table" struct tag.
+type tableTag struct {
+	Name      string
+	Wrap      int
+	OmitEmpty bool
+	Sum       bool
+	Count     bool
+	Format    string
+	Since     bool
+	Precision time.Duration
+	Bytes     bool
+	Flatten   bool
+	Order     int
+	Priority  int
+	Redact    bool
+
+	// Decimals is the number of decimal places of floating point values. It
+	// is only used if HasDecimals is true.
+	Decimals    int
+	HasDecimals bool
+
+	// True and False are printed instead of boolean values if they are not
+	// empty.
+	True, False string
+
+	// MinWidth is the minimum width of the column's content.
+	MinWidth int
+
+	// Merge is true if repeated values in consecutive rows should only be
+	// printed once.
+	Merge bool
+
+	// ID is true if the field identifies its row (see Quiet).
+	ID bool
+}
+
+// parseTableTag parses a struct tag of the form "NAME,option1,option2=value".
+func parseTableTag(tag string) (tableTag, error) {
+	parts := strings.Split(tag, ",")
+	t := tableTag{Name: parts[0]}
+	for _, opt := range parts[1:] {
+		key, value := opt, ""
+		if i := strings.Index(opt, "="); i >= 0 {
+			key, value = opt[:i], opt[i+1:]
+		}
+
+		switch key {
+		case "wrap":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return t, fmt.Errorf("invalid table tag option %q: wrap width must be a positive integer", opt)
+			}
+			t.Wrap = n
+		case "omitempty":
+			t.OmitEmpty = true
+		case "sum":
+			t.Sum = true
+		case "count":
+			t.Count = true
+		case "format":
+			if value == "" {
+				return t, fmt.Errorf("invalid table tag option %q: format must not be empty", opt)
+			}
+			t.Format = value
+		case "since":
+			t.Since = true
+		case "precision":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return t, fmt.Errorf("invalid table tag option %q: precision must be a positive duration", opt)
+			}
+			t.Precision = d
+		case "bytes":
+			t.Bytes = true
+		case "flatten":
+			t.Flatten = true
+		case "order":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return t, fmt.Errorf("invalid table tag option %q: order must be a positive integer", opt)
+			}
+			t.Order = n
+		case "decimals":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return t, fmt.Errorf("invalid table tag option %q: decimals must be a non-negative integer", opt)
+			}
+			t.Decimals, t.HasDecimals = n, true
+		case "minwidth":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return t, fmt.Errorf("invalid table tag option %q: minwidth must be a positive integer", opt)
+			}
+			t.MinWidth = n
+		case "merge":
+			t.Merge = true
+		case "bool":
+			i := strings.Index(value, "/")
+			if i < 0 {
+				return t, fmt.Errorf("invalid table tag option %q: bool must have the form TRUE/FALSE", opt)
+			}
+			t.True, t.False = value[:i], value[i+1:]
+		case "redact":
+			t.Redact = true
+		case "id":
+			t.ID = true
+		case "priority":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return t, fmt.Errorf("invalid table tag option %q: priority must be a positive integer", opt)
+			}
+			t.Priority = n
+		default:
+			return t, fmt.Errorf("unknown table tag option %q", opt)
+		}
+	}
+
+	return t, nil
+}
+
+// formatCell returns the string representation of the value of field f.
+func formatCell(v reflect.Value, f field, opts *options) string {
+	return formatValue(v, f, opts, 0)
+}
+
+// formatValue returns the string representation of v which is nested at the
+// given depth inside the value of field f.
+func formatValue(v reflect.Value, f field, opts *options, depth int) string {
+	if isEmptyValue(v) || f.Tag.OmitEmpty && v.IsZero() {
+		return opts.placeholder
+	}
+
+	v = indirect(v)
+	if isEmptyValue(v) {
+		return opts.placeholder
+	}
+
+	if f.Tag.Redact && !opts.showSecrets && !v.IsZero() {
+		return redactedValue
+	}
+
+	if c, ok := implements(v, tableCellType); ok {
+		return c.(TableCell).TableCell()
+	}
+
+	if f.Tag.Bytes && isNumber(v.Kind()) {
+		return formatBytes(v)
+	}
+
+	switch v.Type() {
+	case timeType:
+		return formatTime(v.Interface().(time.Time), f.Tag)
+	case durationType:
+		return formatDuration(time.Duration(v.Int()), f.Tag.Precision)
+	}
+
+	if s, ok := implements(v, stringerType); ok {
+		return s.(fmt.Stringer).String()
+	}
+
+	if m, ok := implements(v, textMarshalerType); ok {
+		if text, err := m.(encoding.TextMarshaler).MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	if isNumber(v.Kind()) {
+		return formatNumber(v, f.Tag, opts)
+	}
+
+	if v.Kind() == reflect.Bool {
+		return formatBool(v.Bool(), f.Tag, opts)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if depth >= opts.maxDepth {
+			return "…"
+		}
+		if v.Kind() == reflect.Map {
+			return formatMap(v, f, opts, depth)
+		}
+		return formatList(v, f, opts, depth)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// formatList returns the elements of the slice or array v joined by the
+// list separator.
+func formatList(v reflect.Value, f field, opts *options, depth int) string {
+	f.Tag.OmitEmpty = false
+	n := v.Len()
+	if opts.listLimit > 0 && n > opts.listLimit {
+		n = opts.listLimit
+	}
+
+	elems := make([]string, n)
+	for i := range elems {
+		elems[i] = formatValue(v.Index(i), f, opts, depth+1)
+	}
+	return joinList(elems, v.Len(), opts)
+}
+
+// formatMap returns the entries of the map v as "key=value" pairs which are
+// sorted by key and joined by the list separator.
+func formatMap(v reflect.Value, f field, opts *options, depth int) string {
+	f.Tag.OmitEmpty = false
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return compareValues(keys[i], keys[j]) < 0
+	})
+
+	entries := make([]string, len(keys))
+	for i, k := range keys {
+		entries[i] = fmt.Sprint(k.Interface()) + "=" + formatValue(v.MapIndex(k), f, opts, depth+1)
+	}
+	return joinList(entries, len(entries), opts)
+}
+
+// joinList joins the given elements of a list with the given total number of
+// elements with the list separator. If there are more elements than the list
+// limit, the remaining elements are omitted.
+func joinList(elems []string, total int, opts *options) string {
+	if opts.listLimit <= 0 || total <= opts.listLimit {
+		return strings.Join(elems, opts.listSeparator)
+	}
+
+	s := strings.Join(elems[:opts.listLimit], opts.listSeparator)
+	return fmt.Sprintf("%s (+%d more)", s, total-opts.listLimit)
+}
+
+// A TableCell is a value that controls how it is printed in a table cell when
+// using the "table" encoding. Other encodings are not affected by this
+// interface which makes it useful for values such as enums or status codes that
+// should be human readable in tables but remain numeric in JSON or YAML.
+type TableCell interface {
+	TableCell() string
+}
+
+// A TableMarshaler is a value that returns the header and rows of its table
+// itself instead of having them determined via reflection. This is useful for
+// types whose data is computed or unexported as well as for types that are
+// printed very often. It is used by the "table", "detail" and "csv" encodings.
+// Rows that have fewer cells than the header are padded with empty cells.
+type TableMarshaler interface {
+	TableHeader() []string
+	TableRows() [][]string
+}
+
+// marshalTable returns the header and rows of v if it implements the
+// TableMarshaler interface. Each row has exactly as many cells as the header.
+func marshalTable(v interface{}) (header []string, rows [][]string, ok bool) {
+	m, ok := v.(TableMarshaler)
+	if !ok {
+		return nil, nil, false
+	}
+
+	header = m.TableHeader()
+	rows = m.TableRows()
+	for i, row := range rows {
+		if len(row) != len(header) {
+			r := make([]string, len(header))
+			copy(r, row)
+			rows[i] = r
+		}
+	}
+	return header, rows, true
+}
+
+// printTableMarshaler prints the header and rows of a TableMarshaler as table.
+// Only the options that do not require access to the fields of the rows (e.g.
+// Limit, Title, RowNumbers, Border and MaxWidth) are applied.
+func printTableMarshaler(header []string, rows [][]string, w io.Writer, opts *options) error {
+	var more int
+	if opts.limit > 0 && len(rows) > opts.limit {
+		rows, more = rows[:opts.limit], len(rows)-opts.limit
+	}
+
+	layout := &tableLayout{
+		header:    header,
+		parents:   make([]string, len(header)),
+		wraps:     make([]int, len(header)),
+		minWidths: make([]int, len(header)),
+		records:   make([][]string, len(rows)),
+		border:    opts.border,
+		maxWidth:  opts.tableWidth(w),
+	}
+
+	for i, row := range rows {
+		layout.records[i] = make([]string, len(row))
+		for j, cell := range row {
+			if cell == "" {
+				cell = opts.placeholder
+			} else if opts.cellReplacer != nil {
+				cell = opts.cellReplacer.Replace(cell)
+			}
+			layout.records[i][j] = cell
+		}
+	}
+
+	if opts.rowNumbers {
+		layout.header = append([]string{"#"}, layout.header...)
+		layout.parents = append([]string{""}, layout.parents...)
+		layout.wraps = append([]int{0}, layout.wraps...)
+		layout.minWidths = append([]int{0}, layout.minWidths...)
+		for i := range layout.records {
+			layout.records[i] = append([]string{strconv.Itoa(i + 1)}, layout.records[i]...)
+		}
+	}
+
+	if opts.useColor(w) {
+		layout.headerStyle = opts.headerStyle
+	}
+
+	if err := writeTitle(w, opts.useColor(w), opts); err != nil {
+		return err
+	}
+	if err := layout.write(w); err != nil {
+		return err
+	}
+	return writeLimitMessage(w, more, opts)
+}
+
+var (
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+	tableCellType      = reflect.TypeOf((*TableCell)(nil)).Elem()
+	stringerType       = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// implements returns the value of v as interface{} if its type or a pointer to
+// its type implements the given interface type.
+func implements(v reflect.Value, iface reflect.Type) (interface{}, bool) {
+	switch m := implementation(v.Type(), iface); {
+	case m == valueImplements:
+		return v.Interface(), true
+	case m == pointerImplements && v.CanAddr():
+		return v.Addr().Interface(), true
+	default:
+		return nil, false
+	}
+}
+
+// The results of implementation.
+const (
+	notImplemented = iota
+	valueImplements
+	pointerImplements
+)
+
+// implementsKey is the key of the implementsCache.
+type implementsKey struct {
+	Type, Interface reflect.Type
+}
+
+// implementsCache contains the results of implementation since
+// reflect.Type.Implements is too slow to be called for every cell of a table.
+var implementsCache sync.Map // map[implementsKey]int
+
+// implementation returns whether t itself, a pointer to t or neither of them
+// implements the interface type iface.
+func implementation(t, iface reflect.Type) int {
+	key := implementsKey{Type: t, Interface: iface}
+	if m, ok := implementsCache.Load(key); ok {
+		return m.(int)
+	}
+
+	m := notImplemented
+	switch {
+	case t.Implements(iface):
+		m = valueImplements
+	case reflect.PtrTo(t).Implements(iface):
+		m = pointerImplements
+	}
+
+	implementsCache.Store(key, m)
+	return m
+}
+
+// indirect dereferences v until it is neither a pointer nor an interface or
+// until a nil value is found.
+func indirect(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// baseType returns the type that t points to, dereferencing it until it is not
+// a pointer type anymore.
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isNil returns true if v is a nil pointer or interface.
+func isNil(v reflect.Value) bool {
+	return (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil()
+}
+
+// isEmptyValue returns true if v is a nil pointer or interface, an empty slice
+// or map or the zero time.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return isNil(v)
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).IsZero()
+	}
+
+	return false
+}
+
+// escapeCell replaces the characters of s that would break the layout of a
+// table using the CellReplacer option. Newlines of wrapped fields are kept
+// because they are used to break the lines of the cell.
+func escapeCell(s string, f field, opts *options) string {
+	if opts.cellReplacer == nil {
+		return s
+	}
+
+	if f.Tag.Wrap == 0 {
+		return opts.cellReplacer.Replace(s)
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = opts.cellReplacer.Replace(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrap splits s into lines of at most width runes. Lines are broken at white
+// space if possible. Words that are longer than width are split. Existing
+// newlines in s are preserved.
+func wrap(s string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		var line []rune
+		for _, word := range strings.Fields(paragraph) {
+			w := []rune(word)
+			if len(line) > 0 && len(line)+1+len(w) > width {
+				lines = append(lines, string(line))
+				line = nil
+			}
+
+			for len(w) > width {
+				if len(line) > 0 {
+					lines = append(lines, string(line))
+					line = nil
+				}
+				lines = append(lines, string(w[:width]))
+				w = w[width:]
+			}
+
+			if len(line) > 0 {
+				line = append(line, ' ')
+			}
+			line = append(line, w...)
+		}
+		lines = append(lines, string(line))
+	}
+
+	return lines
+}
@@ -0,0 +1,1578 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintTable(t *testing.T) {
+	cases := map[string]struct {
+		instance interface{}
+		expected []string
+	}{
+		"no tags": {
+			instance: struct {
+				Name  string
+				Age   int
+				Value bool
+			}{
+				Name:  "Test",
+				Age:   42,
+				Value: true,
+			},
+			expected: []string{
+				"NAME    AGE     VALUE",
+				"Test    42      true",
+			},
+		},
+		"with ignore tags": {
+			instance: struct {
+				Name  string
+				Age   int
+				Value bool `table:"-"`
+			}{
+				Name:  "Test",
+				Age:   42,
+				Value: true,
+			},
+			expected: []string{
+				"NAME    AGE",
+				"Test    42",
+			},
+		},
+		"rename columns": {
+			instance: struct {
+				Name  string `table:"key"`
+				Age   int    `table:"age"`
+				Value bool   `table:"-"`
+			}{
+				Name:  "Test",
+				Age:   42,
+				Value: true,
+			},
+			expected: []string{
+				"key     age",
+				"Test    42",
+			},
+		},
+		"slice": {
+			instance: []struct {
+				Name  string
+				Age   int
+				Value bool
+			}{
+				{Name: "Foo", Age: 1, Value: true},
+				{Name: "Bar", Age: 2, Value: false},
+				{Name: "Baz", Age: 3, Value: false},
+			},
+			expected: []string{
+				"NAME    AGE     VALUE",
+				"Foo     1       true",
+				"Bar     2       false",
+				"Baz     3       false",
+			},
+		},
+		"wrapped column": {
+			instance: []struct {
+				Name    string
+				Message string `table:"MESSAGE,wrap=10"`
+				Age     int
+			}{
+				{Name: "Foo", Message: "this message is too long for one line", Age: 1},
+				{Name: "Bar", Message: "short", Age: 2},
+			},
+			expected: []string{
+				"NAME    MESSAGE     AGE",
+				"Foo     this        1",
+				"        message is",
+				"        too long",
+				"        for one",
+				"        line",
+				"Bar     short       2",
+			},
+		},
+		"slice of strings": {
+			instance: []string{"A", "B", "C"},
+			expected: []string{
+				"A",
+				"B",
+				"C",
+			},
+		},
+		"slice of ints": {
+			instance: []int{1, 2, 3},
+			expected: []string{
+				"1",
+				"2",
+				"3",
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("table", c.instance, out))
+			assert.Equal(t, strings.Join(c.expected, "\n")+"\n", out.String())
+		})
+	}
+}
+
+func TestPrintTable_InvalidTag(t *testing.T) {
+	v := struct {
+		Message string `table:"MESSAGE,wrap=x"`
+	}{}
+
+	err := PrintWriter("table", v, new(bytes.Buffer))
+	assert.Error(t, err)
+}
+
+func TestWrap(t *testing.T) {
+	cases := map[string]struct {
+		input    string
+		width    int
+		expected []string
+	}{
+		"empty":      {"", 5, []string{""}},
+		"fits":       {"foo bar", 10, []string{"foo bar"}},
+		"words":      {"foo bar baz", 7, []string{"foo bar", "baz"}},
+		"long word":  {"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		"newlines":   {"foo\nbar", 10, []string{"foo", "bar"}},
+		"whitespace": {"  foo   bar  ", 10, []string{"foo bar"}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, wrap(c.input, c.width))
+		})
+	}
+}
+
+func TestPrintTable_SortBy(t *testing.T) {
+	type row struct {
+		Name string
+		Age  int
+		Code string `table:"ID"`
+	}
+
+	rows := []row{
+		{Name: "Foo", Age: 10, Code: "10"},
+		{Name: "Bar", Age: 9, Code: "9"},
+		{Name: "Baz", Age: 100, Code: "100"},
+	}
+
+	cases := map[string]struct {
+		column   string
+		desc     bool
+		expected []string
+	}{
+		"strings":         {"name", false, []string{"Bar", "Baz", "Foo"}},
+		"strings desc":    {"NAME", true, []string{"Foo", "Baz", "Bar"}},
+		"ints":            {"age", false, []string{"Bar", "Foo", "Baz"}},
+		"ints desc":       {"age", true, []string{"Baz", "Foo", "Bar"}},
+		"numeric strings": {"id", false, []string{"Bar", "Foo", "Baz"}},
+		"field name":      {"Code", false, []string{"Bar", "Foo", "Baz"}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("table", rows, out, SortBy(c.column, c.desc)))
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			require.Len(t, lines, len(c.expected)+1)
+			for i, expected := range c.expected {
+				assert.Equal(t, expected, strings.Fields(lines[i+1])[0])
+			}
+		})
+	}
+
+	err := PrintWriter("table", rows, new(bytes.Buffer), SortBy("foo", false))
+	assert.EqualError(t, err, `cannot sort by unknown column "foo"`)
+}
+
+func TestPrintTable_SortByMixedKinds(t *testing.T) {
+	type row struct {
+		Name  string
+		Value interface{}
+	}
+
+	cases := map[string][]row{
+		"string first": {{"A", "x"}, {"B", 1.5}, {"C", 2}},
+		"number first": {{"C", 2}, {"B", 1.5}, {"A", "x"}},
+	}
+
+	for name, rows := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("table", rows, out, SortBy("value", false)))
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			require.Len(t, lines, 4)
+			assert.Equal(t, "B", strings.Fields(lines[1])[0])
+			assert.Equal(t, "C", strings.Fields(lines[2])[0])
+			assert.Equal(t, "A", strings.Fields(lines[3])[0])
+		})
+	}
+}
+
+func TestPrintTable_Columns(t *testing.T) {
+	rows := []struct {
+		Name  string
+		Age   int
+		Value bool
+	}{
+		{Name: "Foo", Age: 1, Value: true},
+		{Name: "Bar", Age: 2, Value: false},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, Columns("value", "NAME")))
+	expected := []string{
+		"VALUE   NAME",
+		"true    Foo",
+		"false   Bar",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", rows, new(bytes.Buffer), Columns("name", "foo"))
+	assert.EqualError(t, err, `unknown column "foo"`)
+}
+
+func TestPrintTable_HideEmptyColumns(t *testing.T) {
+	type row struct {
+		Name    string
+		Comment string
+		Age     int
+		Labels  map[string]string
+	}
+
+	rows := []row{
+		{Name: "Foo", Age: 1},
+		{Name: "Bar"},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, HideEmptyColumns()))
+	expected := []string{
+		"NAME    AGE",
+		"Foo     1",
+		"Bar     0",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", []row{}, out, HideEmptyColumns()))
+	assert.Equal(t, "NAME    COMMENT  AGE     LABELS\n", out.String())
+}
+
+func TestPrintTable_OmitEmpty(t *testing.T) {
+	rows := []struct {
+		Name  string
+		Age   int  `table:"AGE,omitempty"`
+		Value bool `table:",omitempty"`
+	}{
+		{Name: "Foo", Age: 1, Value: true},
+		{Name: "Bar"},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out))
+	expected := []string{
+		"NAME    AGE     VALUE",
+		"Foo     1       true",
+		"Bar     -       -",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Placeholder("")))
+	expected = []string{
+		"NAME    AGE     VALUE",
+		"Foo     1       true",
+		"Bar",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTable_Placeholder(t *testing.T) {
+	name := "Foo"
+	rows := []struct {
+		Name    *string
+		Labels  []string
+		Created time.Time
+		Extra   interface{}
+	}{
+		{Name: &name, Labels: []string{"a"}, Created: time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC), Extra: 42},
+		{Labels: []string{}},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, Columns("labels", "created", "extra")))
+	expected := []string{
+		"LABELS  CREATED               EXTRA",
+		"a       2018-01-02T03:04:05Z  42",
+		"-       -                     -",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Columns("name"), Placeholder("<none>")))
+	assert.Contains(t, out.String(), "<none>")
+}
+
+func TestPrintTable_Footer(t *testing.T) {
+	type row struct {
+		Name   string  `table:"NAME,count"`
+		Amount int     `table:"AMOUNT,sum"`
+		Price  float64 `table:"PRICE,sum"`
+	}
+
+	rows := []row{
+		{Name: "Foo", Amount: 1, Price: 0.5},
+		{Name: "Bar", Amount: 2, Price: 1.25},
+		{Amount: 3},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out))
+	expected := []string{
+		"NAME    AMOUNT  PRICE",
+		"Foo     1       0.5",
+		"Bar     2       1.25",
+		"        3       0",
+		"2       6       1.75",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Footer(map[string]string{"name": "TOTAL"})))
+	assert.True(t, strings.HasSuffix(out.String(), "TOTAL   6       1.75\n"), out.String())
+
+	err := PrintWriter("table", rows, new(bytes.Buffer), Footer(map[string]string{"foo": "bar"}))
+	assert.EqualError(t, err, `unknown footer column "foo"`)
+
+	err = PrintWriter("table", []struct {
+		Name string `table:",sum"`
+	}{}, new(bytes.Buffer))
+	assert.EqualError(t, err, "field Name: sum option requires a numeric field")
+}
+
+func TestPrintTable_RowNumbers(t *testing.T) {
+	rows := []struct {
+		Name   string
+		Amount int `table:"AMOUNT,sum"`
+	}{
+		{Name: "Foo", Amount: 1},
+		{Name: "Bar", Amount: 2},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, RowNumbers(), SortBy("name", false)))
+	expected := []string{
+		"#       NAME    AMOUNT",
+		"1       Bar     2",
+		"2       Foo     1",
+		"                3",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTable_Styles(t *testing.T) {
+	type row struct {
+		Name   string
+		Status string
+	}
+
+	rows := []row{
+		{Name: "Foo", Status: "ok"},
+		{Name: "Bar", Status: "failed"},
+	}
+
+	cellStyle := CellStyle(func(r interface{}, column string) Style {
+		if r.(row).Status == "failed" {
+			return Red
+		}
+		return ""
+	})
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, Color(ColorAlways), HeaderStyle(Bold), cellStyle))
+	expected := []string{
+		"\x1b[1mNAME\x1b[0m    \x1b[1mSTATUS\x1b[0m",
+		"Foo     ok",
+		"\x1b[31mBar\x1b[0m     \x1b[31mfailed\x1b[0m",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	// The buffer is not a terminal so no styles should be applied by default.
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, HeaderStyle(Bold), cellStyle))
+	expected = []string{
+		"NAME    STATUS",
+		"Foo     ok",
+		"Bar     failed",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTable_Border(t *testing.T) {
+	rows := []struct {
+		Name   string
+		Amount int `table:"AMOUNT,sum"`
+	}{
+		{Name: "Foo bar", Amount: 1},
+		{Name: "Baz", Amount: 20},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, Border(BorderASCII)))
+	expected := []string{
+		"+---------+--------+",
+		"| NAME    | AMOUNT |",
+		"+---------+--------+",
+		"| Foo bar | 1      |",
+		"| Baz     | 20     |",
+		"+---------+--------+",
+		"|         | 21     |",
+		"+---------+--------+",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows[:1], out, Border(BorderUnicode), Columns("name")))
+	expected = []string{
+		"┌─────────┐",
+		"│ NAME    │",
+		"├─────────┤",
+		"│ Foo bar │",
+		"└─────────┘",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+type testStatus int
+
+func (s testStatus) TableCell() string {
+	switch s {
+	case 1:
+		return "running"
+	case 2:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+type testCode struct {
+	Value string
+}
+
+func (c *testCode) TableCell() string {
+	return "code-" + c.Value
+}
+
+func TestPrintTable_TableCell(t *testing.T) {
+	rows := []struct {
+		Status testStatus
+		Code   testCode
+	}{
+		{Status: 1, Code: testCode{"a"}},
+		{Status: 0, Code: testCode{"b"}},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out))
+	expected := []string{
+		"STATUS   CODE",
+		"running  code-a",
+		"unknown  code-b",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("json", rows[0], out))
+	assert.Contains(t, out.String(), `"Status": 1`)
+}
+
+func TestPrintTable_Time(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2018, 1, 2, 6, 0, 0, 0, time.UTC) }
+
+	created := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	v := struct {
+		Default time.Time
+		Date    time.Time `table:"DATE,format=2006-01-02"`
+		Age     time.Time `table:"AGE,since"`
+	}{created, created, created}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", v, out))
+	expected := []string{
+		"DEFAULT               DATE        AGE",
+		"2018-01-02T03:04:05Z  2018-01-02  2h ago",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", struct {
+		Name string `table:",since"`
+	}{}, out)
+	assert.EqualError(t, err, "field Name: format and since options require a time.Time field")
+}
+
+func TestPrintTable_Duration(t *testing.T) {
+	v := struct {
+		Took    time.Duration
+		Elapsed time.Duration `table:",precision=1h"`
+	}{
+		Took:    72 * time.Minute,
+		Elapsed: 90 * time.Minute,
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", v, out))
+	expected := []string{
+		"TOOK    ELAPSED",
+		"1h12m   2h",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", struct {
+		Took int `table:",precision=1s"`
+	}{}, out)
+	assert.EqualError(t, err, "field Took: precision option requires a time.Duration field")
+}
+
+func TestPrintTable_Bytes(t *testing.T) {
+	rows := []struct {
+		Name string
+		Size int64 `table:"SIZE,bytes,sum"`
+	}{
+		{Name: "a", Size: 340 << 20},
+		{Name: "b", Size: 1 << 30},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out))
+	expected := []string{
+		"NAME    SIZE",
+		"a       340 MiB",
+		"b       1 GiB",
+		"        1.3 GiB",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", struct {
+		Size string `table:",bytes"`
+	}{}, out)
+	assert.EqualError(t, err, "field Size: bytes option requires a numeric field")
+}
+
+type testID struct {
+	prefix string
+	n      int
+}
+
+func (id *testID) String() string {
+	return fmt.Sprintf("%s-%d", id.prefix, id.n)
+}
+
+type testLevel int
+
+func (l testLevel) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+func TestPrintTable_StringerAndTextMarshaler(t *testing.T) {
+	rows := []struct {
+		ID    testID
+		Level testLevel
+	}{
+		{ID: testID{"foo", 1}, Level: 3},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out))
+	expected := []string{
+		"ID      LEVEL",
+		"foo-1   ***",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTable_Pointers(t *testing.T) {
+	name, n, id := "Foo", 42, testID{"id", 7}
+	type row struct {
+		Name   *string
+		Amount *int `table:",sum"`
+		ID     *testID
+	}
+
+	rows := []row{
+		{Name: &name, Amount: &n, ID: &id},
+		{},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, SortBy("amount", false)))
+	expected := []string{
+		"NAME    AMOUNT  ID",
+		"-       -       -",
+		"Foo     42      id-7",
+		"        42",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTable_Lists(t *testing.T) {
+	v := struct {
+		Tags   []string
+		Ports  [2]int
+		Labels map[string]string
+		Sizes  map[int]int64 `table:",bytes"`
+	}{
+		Tags:   []string{"a", "b", "c"},
+		Ports:  [2]int{80, 443},
+		Labels: map[string]string{"foo": "bar", "app": "test"},
+		Sizes:  map[int]int64{10: 2048, 9: 1024},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", v, out))
+	expected := []string{
+		"TAGS    PORTS   LABELS            SIZES",
+		"a,b,c   80,443  app=test,foo=bar  9=1 KiB,10=2 KiB",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", v, out, ListSeparator(" "), ListLimit(2), Columns("tags")))
+	expected = []string{
+		"TAGS",
+		"a b (+1 more)",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	// Maps decoded from YAML may have keys of different kinds.
+	out.Reset()
+	mixed := []struct{ Labels map[interface{}]interface{} }{
+		{Labels: map[interface{}]interface{}{"b": 2, 1: "a", 2: "c", "z": 1}},
+	}
+	require.NoError(t, PrintWriter("table", mixed, out))
+	expected = []string{
+		"LABELS",
+		"1=a,2=c,b=2,z=1",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTable_GroupBy(t *testing.T) {
+	rows := []struct {
+		Name        string
+		Environment string `table:"ENV"`
+		Version     string
+	}{
+		{Name: "api", Environment: "production", Version: "1.2"},
+		{Name: "web", Environment: "staging", Version: "1.3"},
+		{Name: "database", Environment: "production", Version: "9.6"},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, GroupBy("env")))
+	expected := []string{
+		"NAME      VERSION",
+		"ENV: production",
+		"api       1.2",
+		"database  9.6",
+		"",
+		"ENV: staging",
+		"web       1.3",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, GroupBy("env"), Border(BorderASCII)))
+	expected = []string{
+		"+----------+---------+",
+		"| NAME     | VERSION |",
+		"+----------+---------+",
+		"| ENV: production    |",
+		"+----------+---------+",
+		"| api      | 1.2     |",
+		"| database | 9.6     |",
+		"+----------+---------+",
+		"| ENV: staging       |",
+		"+----------+---------+",
+		"| web      | 1.3     |",
+		"+----------+---------+",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", rows, out, GroupBy("foo"))
+	assert.EqualError(t, err, `cannot group by unknown column "foo"`)
+}
+
+func TestPrintTable_Flatten(t *testing.T) {
+	type owner struct {
+		Name  string
+		Email string
+	}
+
+	type row struct {
+		ID      int
+		Owner   owner  `table:",flatten"`
+		Backup  *owner `table:",flatten"`
+		Comment string
+	}
+
+	rows := []row{
+		{ID: 1, Owner: owner{"Foo", "foo@example.com"}, Backup: &owner{Name: "Bar"}, Comment: "test"},
+		{ID: 2, Owner: owner{"Baz", "baz@example.com"}},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, SortBy("owner.email", true), Columns("id", "owner.name", "Owner.Email", "backup.name")))
+	expected := []string{
+		"ID      OWNER.NAME  OWNER.EMAIL      BACKUP.NAME",
+		"1       Foo         foo@example.com  Bar",
+		"2       Baz         baz@example.com  -",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, SpanningHeaders()))
+	expected = []string{
+		"        OWNER                    BACKUP",
+		"ID      NAME    EMAIL            NAME    EMAIL   COMMENT",
+		"1       Foo     foo@example.com  Bar             test",
+		"2       Baz     baz@example.com  -       -",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, SpanningHeaders(), Border(BorderASCII), Columns("id", "owner.name", "owner.email")))
+	expected = []string{
+		"+----+------------------------+",
+		"|    | OWNER                  |",
+		"+----+------+-----------------+",
+		"| ID | NAME | EMAIL           |",
+		"+----+------+-----------------+",
+		"| 1  | Foo  | foo@example.com |",
+		"| 2  | Baz  | baz@example.com |",
+		"+----+------+-----------------+",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", struct {
+		Name string `table:",flatten"`
+	}{}, out)
+	assert.EqualError(t, err, "field Name: flatten option requires a struct field")
+}
+
+func TestPrintTable_Limit(t *testing.T) {
+	rows := []struct {
+		Name   string
+		Amount int `table:",sum"`
+	}{
+		{Name: "Foo", Amount: 1},
+		{Name: "Bar", Amount: 2},
+		{Name: "Baz", Amount: 3},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, Limit(1)))
+	expected := []string{
+		"NAME    AMOUNT",
+		"Foo     1",
+		"        6",
+		"... 2 more rows",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Limit(3), LimitMessage("%d more")))
+	assert.NotContains(t, out.String(), "more")
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Limit(2), LimitMessage("%d more (use --limit 0 to show all)")))
+	assert.True(t, strings.HasSuffix(out.String(), "\n1 more (use --limit 0 to show all)\n"))
+}
+
+func TestPrintTable_Order(t *testing.T) {
+	type owner struct {
+		Email string
+		Name  string `table:",order=1"`
+	}
+
+	rows := []struct {
+		ID      int
+		Comment string
+		Name    string `table:",order=2"`
+		Owner   owner  `table:",flatten,order=1"`
+	}{
+		{ID: 1, Comment: "test", Name: "Foo", Owner: owner{Email: "bar@example.com", Name: "Bar"}},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out))
+	expected := []string{
+		"OWNER.NAME  OWNER.EMAIL      NAME    ID      COMMENT",
+		"Bar         bar@example.com  Foo     1       test",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", struct {
+		Name string `table:",order=0"`
+	}{}, out)
+	assert.EqualError(t, err, `field Name: invalid table tag option "order=0": order must be a positive integer`)
+}
+
+func TestPrintTable_MaxWidth(t *testing.T) {
+	rows := []struct {
+		ID          int
+		Name        string
+		Description string
+		Region      string `table:",priority=2"`
+		Zone        string `table:",priority=1"`
+	}{
+		{ID: 1, Name: "Foo", Description: "A rather long description", Region: "eu-west-1", Zone: "a"},
+		{ID: 2, Name: "Bar", Description: "Short", Region: "us-east-1", Zone: "b"},
+	}
+
+	tests := map[string]struct {
+		opts     []Option
+		expected []string
+	}{
+		"fits": {
+			opts: []Option{MaxWidth(80)},
+			expected: []string{
+				"ID      NAME    DESCRIPTION                REGION     ZONE",
+				"1       Foo     A rather long description  eu-west-1  a",
+				"2       Bar     Short                      us-east-1  b",
+			},
+		},
+		"drop columns": {
+			opts: []Option{MaxWidth(55)},
+			expected: []string{
+				"ID      NAME    DESCRIPTION                ZONE",
+				"1       Foo     A rather long description  a",
+				"2       Bar     Short                      b",
+			},
+		},
+		"truncate": {
+			opts: []Option{MaxWidth(30)},
+			expected: []string{
+				"ID      NAME    DESCRIPTION",
+				"1       Foo     A rather lo…",
+				"2       Bar     Short",
+			},
+		},
+		"border": {
+			opts: []Option{MaxWidth(30), Border(BorderASCII)},
+			expected: []string{
+				"+----+------+----------------+",
+				"| ID | NAME | DESCRIPTION    |",
+				"+----+------+----------------+",
+				"| 1  | Foo  | A rather long… |",
+				"| 2  | Bar  | Short          |",
+				"+----+------+----------------+",
+			},
+		},
+		"drop and truncate": {
+			opts: []Option{MaxWidth(24), Columns("name", "description", "region")},
+			expected: []string{
+				"NAME    DESCRIPTION",
+				"Foo     A rather long…",
+				"Bar     Short",
+			},
+		},
+		"unlimited": {
+			opts: []Option{MaxWidth(0), Columns("id", "description")},
+			expected: []string{
+				"ID      DESCRIPTION",
+				"1       A rather long description",
+				"2       Short",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("table", rows, out, tt.opts...))
+			assert.Equal(t, strings.Join(tt.expected, "\n")+"\n", out.String())
+		})
+	}
+}
+
+func TestPrintTable_CellReplacer(t *testing.T) {
+	rows := []struct {
+		Name  string
+		Error string
+		Notes string `table:",wrap=20"`
+	}{
+		{Name: "Foo", Error: "first\nsecond\tthird", Notes: "a\tb\nc"},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out))
+	expected := []string{
+		`NAME    ERROR                 NOTES`,
+		`Foo     first\nsecond\tthird  a\tb`,
+		`                              c`,
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Columns("error"), CellReplacer(strings.NewReplacer("\n", " ", "\t", " "))))
+	expected = []string{
+		"ERROR",
+		"first second third",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+}
+
+func TestPrintTable_Interfaces(t *testing.T) {
+	type row struct {
+		Name string
+		Age  int
+	}
+
+	tests := map[string]struct {
+		instance interface{}
+		expected []string
+	}{
+		"same type": {
+			instance: []interface{}{row{"Foo", 1}, &row{"Bar", 2}, nil},
+			expected: []string{
+				"NAME    AGE",
+				"Foo     1",
+				"Bar     2",
+				"-       -",
+			},
+		},
+		"pointers": {
+			instance: []*row{{"Foo", 1}},
+			expected: []string{
+				"NAME    AGE",
+				"Foo     1",
+			},
+		},
+		"mixed types": {
+			instance: []interface{}{row{"Foo", 1}, "bar", 42},
+			expected: []string{
+				"{Foo 1}",
+				"bar",
+				"42",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("table", tt.instance, out))
+			assert.Equal(t, strings.Join(tt.expected, "\n")+"\n", out.String())
+		})
+	}
+}
+
+func TestPrintTable_Numbers(t *testing.T) {
+	rows := []struct {
+		Name  string
+		Rows  int64   `table:",sum"`
+		Ratio float64 `table:",decimals=2"`
+	}{
+		{Name: "Foo", Rows: 1234567890, Ratio: 0.125},
+		{Name: "Bar", Rows: 42, Ratio: 1},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, NumberFormat(",", ".")))
+	expected := []string{
+		"NAME    ROWS           RATIO",
+		"Foo     1,234,567,890  0.12",
+		"Bar     42             1.00",
+		"        1,234,567,932",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", struct {
+		Count int `table:",decimals=2"`
+	}{}, out)
+	assert.EqualError(t, err, "field Count: decimals option requires a floating point field")
+}
+
+func TestPrintTable_Booleans(t *testing.T) {
+	rows := []struct {
+		Name    string
+		Enabled bool
+		Public  *bool `table:",bool=yes/no"`
+	}{
+		{Name: "Foo", Enabled: true},
+		{Name: "Bar", Public: new(bool)},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, Booleans("✓", "✗")))
+	expected := []string{
+		"NAME    ENABLED  PUBLIC",
+		"Foo     ✓        -",
+		"Bar     ✗        no",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Columns("enabled", "public"), RowNumbers(), Color(ColorAlways), BooleanStyles(Green, Red)))
+	expected = []string{
+		"#       ENABLED  PUBLIC",
+		"1       \x1b[32mtrue\x1b[0m     -",
+		"2       \x1b[31mfalse\x1b[0m    \x1b[31mno\x1b[0m",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", struct {
+		Name string `table:",bool=yes/no"`
+	}{}, out)
+	assert.EqualError(t, err, "field Name: bool option requires a boolean field")
+}
+
+func TestPrintTable_HeaderCasing(t *testing.T) {
+	rows := []struct {
+		UserID    int
+		CreatedAt string
+		Comment   string `table:"NOTE"`
+	}{
+		{UserID: 1, CreatedAt: "today", Comment: "test"},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, HeaderCasing(HeaderTitleCase), SortBy("created at", false)))
+	expected := []string{
+		"User ID  Created At  NOTE",
+		"1        today       test",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("detail", rows[0], out, HeaderCasing(HeaderSnakeCase), Columns("user_id", "CreatedAt")))
+	assert.Equal(t, "user_id:    1\ncreated_at: today\n", out.String())
+}
+
+func TestPrintTable_Title(t *testing.T) {
+	rows := []struct{ Name string }{{Name: "Foo"}}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, Title("Users")))
+	assert.Equal(t, "Users\nNAME\nFoo\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Title("Users"), Border(BorderASCII), Color(ColorAlways), HeaderStyle(Bold)))
+	expected := []string{
+		"\x1b[1mUsers\x1b[0m",
+		"+------+",
+		"| \x1b[1mNAME\x1b[0m |",
+		"+------+",
+		"| Foo  |",
+		"+------+",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("detail", rows, out, Title("Users")))
+	assert.Equal(t, "Users\nNAME: Foo\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("json", rows, out, Title("Users")))
+	assert.NotContains(t, out.String(), "Users")
+}
+
+func TestPrintTable_MinWidth(t *testing.T) {
+	rows := []struct {
+		Name   string `table:",minwidth=10"`
+		Status string
+		Age    int
+	}{
+		{Name: "Foo", Status: "ok", Age: 1},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, MinColumnWidth("status", 8)))
+	expected := []string{
+		"NAME        STATUS    AGE",
+		"Foo         ok        1",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, Border(BorderASCII), Columns("name", "age")))
+	expected = []string{
+		"+------------+-----+",
+		"| NAME       | AGE |",
+		"+------------+-----+",
+		"| Foo        | 1   |",
+		"+------------+-----+",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", rows, out, MinColumnWidth("foo", 8))
+	assert.EqualError(t, err, `unknown column "foo"`)
+}
+
+func TestPrintTable_HighlightIf(t *testing.T) {
+	type check struct {
+		Name    string
+		Healthy bool
+	}
+
+	rows := []check{
+		{Name: "db", Healthy: true},
+		{Name: "cache", Healthy: false},
+	}
+
+	failed := HighlightIf(func(r interface{}) bool {
+		return !r.(check).Healthy
+	}, Red)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, Color(ColorAlways), failed, BooleanStyles("", Bold)))
+	expected := []string{
+		"NAME    HEALTHY",
+		"db      true",
+		"\x1b[31mcache\x1b[0m   \x1b[31;1mfalse\x1b[0m",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, failed))
+	assert.NotContains(t, out.String(), "\x1b")
+}
+
+func TestPrintTable_MergeRepeated(t *testing.T) {
+	type disk struct {
+		Host   string `table:",merge"`
+		Device string
+		Size   int
+	}
+
+	rows := []disk{
+		{Host: "a", Device: "sda", Size: 1},
+		{Host: "a", Device: "sda", Size: 2},
+		{Host: "b", Device: "sda", Size: 2},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out))
+	expected := []string{
+		"HOST    DEVICE  SIZE",
+		"a       sda     1",
+		"        sda     2",
+		"b       sda     2",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, MergeRepeated()))
+	expected = []string{
+		"HOST    DEVICE  SIZE",
+		"a       sda     1",
+		"                2",
+		"b       sda     2",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", rows, out, MergeRepeated("size"), GroupBy("host")))
+	expected = []string{
+		"DEVICE  SIZE",
+		"HOST: a",
+		"sda     1",
+		"sda     2",
+		"",
+		"HOST: b",
+		"sda     2",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", rows, out, MergeRepeated("foo"))
+	assert.EqualError(t, err, `unknown column "foo"`)
+}
+
+func TestPrintTable_SplitBy(t *testing.T) {
+	type resource struct {
+		Kind     string
+		Name     string
+		Replicas int `table:",sum"`
+		Schedule string
+	}
+
+	rows := []resource{
+		{Kind: "Deployment", Name: "api", Replicas: 3},
+		{Kind: "CronJob", Name: "backup", Schedule: "@daily"},
+		{Kind: "Deployment", Name: "web", Replicas: 2},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", rows, out, SplitBy("kind"), HideEmptyColumns(), SortBy("name", false)))
+	expected := []string{
+		"KIND: Deployment",
+		"NAME    REPLICAS",
+		"api     3",
+		"web     2",
+		"        5",
+		"",
+		"KIND: CronJob",
+		"NAME    SCHEDULE",
+		"backup  @daily",
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", out.String())
+
+	err := PrintWriter("table", rows, out, SplitBy("foo"))
+	assert.EqualError(t, err, `cannot split by unknown column "foo"`)
+}
+
+func TestPrintTable_Fallback(t *testing.T) {
+	type user struct {
+		Name  string
+		Admin bool `table:"ADMIN,bool=yes/no"`
+	}
+
+	cases := map[string]struct {
+		value    interface{}
+		expected []string
+	}{
+		"string": {
+			value:    "hello world",
+			expected: []string{"hello world"},
+		},
+		"int": {
+			value:    42,
+			expected: []string{"42"},
+		},
+		"map": {
+			value: map[string]int{"b": 2, "a": 1},
+			expected: []string{
+				"KEY     VALUE",
+				"a       1",
+				"b       2",
+			},
+		},
+		"map of structs": {
+			value: map[int]*user{2: {Name: "bob"}, 1: {Name: "alice", Admin: true}, 3: nil},
+			expected: []string{
+				"KEY     NAME    ADMIN",
+				"1       alice   yes",
+				"2       bob     no",
+				"3       -       -",
+			},
+		},
+		"map with mixed keys": {
+			value: map[interface{}]interface{}{"b": 2, 1: "a", 2: "c", "z": 1},
+			expected: []string{
+				"KEY     VALUE",
+				"1       a",
+				"2       c",
+				"b       2",
+				"z       1",
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("table", c.value, out))
+			assert.Equal(t, strings.Join(c.expected, "\n")+"\n", out.String())
+		})
+	}
+}
+
+func TestTableFields_Cache(t *testing.T) {
+	type row struct {
+		UserName string
+		Age      int `table:",sum"`
+	}
+
+	typ := reflect.TypeOf(row{})
+	fields, err := tableFields(typ, newOptions(nil))
+	require.NoError(t, err)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "USERNAME", fields[0].Name)
+
+	// Callers may modify the returned fields without affecting the cache.
+	fields[0].Tag.MinWidth = 20
+	fields = append(fields[:0], fields[1])
+
+	fields, err = tableFields(typ, newOptions(nil))
+	require.NoError(t, err)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "USERNAME", fields[0].Name)
+	assert.Zero(t, fields[0].Tag.MinWidth)
+
+	fields, err = tableFields(typ, newOptions([]Option{HeaderCasing(HeaderSnakeCase)}))
+	require.NoError(t, err)
+	assert.Equal(t, "user_name", fields[0].Name)
+}
+
+type matrix [][]int
+
+func (m matrix) TableHeader() []string {
+	header := make([]string, len(m))
+	for i := range m {
+		header[i] = fmt.Sprintf("C%d", i+1)
+	}
+	return header
+}
+
+func (m matrix) TableRows() [][]string {
+	rows := make([][]string, len(m))
+	for i, row := range m {
+		for _, v := range row {
+			rows[i] = append(rows[i], fmt.Sprint(v))
+		}
+	}
+	return rows
+}
+
+func TestPrintTable_TableMarshaler(t *testing.T) {
+	m := matrix{{1, 0, 0}, {0, 1}, {0, 0, 1}}
+
+	cases := map[string]struct {
+		encoding string
+		opts     []Option
+		expected []string
+	}{
+		"table": {
+			encoding: "table",
+			opts:     []Option{RowNumbers(), Limit(2)},
+			expected: []string{
+				"#       C1      C2      C3",
+				"1       1       0       0",
+				"2       0       1       -",
+				"... 1 more rows",
+			},
+		},
+		"detail": {
+			encoding: "detail",
+			opts:     []Option{Limit(2)},
+			expected: []string{
+				"C1: 1",
+				"C2: 0",
+				"C3: 0",
+				"",
+				"C1: 0",
+				"C2: 1",
+				"C3: -",
+				"",
+				"... 1 more rows",
+			},
+		},
+		"csv": {
+			encoding: "csv",
+			expected: []string{
+				"C1,C2,C3",
+				"1,0,0",
+				"0,1,",
+				"0,0,1",
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter(c.encoding, m, out, c.opts...))
+			assert.Equal(t, strings.Join(c.expected, "\n")+"\n", out.String())
+		})
+	}
+}
+
+func BenchmarkPrintTable(b *testing.B) {
+	type row struct {
+		ID      int
+		Name    string
+		Email   string
+		Created time.Time
+		Active  bool
+		Score   float64
+	}
+
+	rows := make([]row, 10000)
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := range rows {
+		rows[i] = row{
+			ID:      i,
+			Name:    fmt.Sprintf("user-%d", i),
+			Email:   fmt.Sprintf("user-%d@example.com", i),
+			Created: created.Add(time.Duration(i) * time.Minute),
+			Active:  i%2 == 0,
+			Score:   float64(i) / 3,
+		}
+	}
+
+	out := new(bytes.Buffer)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out.Reset()
+		if err := PrintWriter("table", rows, out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPrintTable_MaxWidth(b *testing.B) {
+	type row struct {
+		ID          int
+		Name        string
+		Description string
+	}
+
+	rows := make([]row, 1000)
+	for i := range rows {
+		rows[i] = row{
+			ID:          i,
+			Name:        fmt.Sprintf("user-%d", i),
+			Description: strings.Repeat("x", 2000),
+		}
+	}
+
+	out := new(bytes.Buffer)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out.Reset()
+		if err := PrintWriter("table", rows, out, MaxWidth(80)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPrintTable_MaxDepth(t *testing.T) {
+	type node struct {
+		Name   string
+		Parent *node `table:",flatten"`
+	}
+
+	err := PrintWriter("table", node{Name: "root"}, new(bytes.Buffer))
+	assert.EqualError(t, err, "field Parent: cannot flatten recursive type cli.node")
+
+	type level3 struct{ Value int }
+	type level2 struct {
+		Level3 level3 `table:",flatten"`
+	}
+	type level1 struct {
+		Level2 level2 `table:",flatten"`
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, PrintWriter("table", level1{}, out))
+	assert.Equal(t, "LEVEL2.LEVEL3.VALUE\n0\n", out.String())
+
+	err = PrintWriter("table", level1{}, out, MaxDepth(1))
+	assert.EqualError(t, err, "field Level2: field Level3: flatten exceeds the maximum depth of 1")
+
+	type row struct {
+		Values []interface{}
+	}
+
+	values := []interface{}{1, nil}
+	values[1] = values
+
+	out.Reset()
+	require.NoError(t, PrintWriter("table", row{Values: values}, out, MaxDepth(3)))
+	assert.Equal(t, "VALUES\n1,1,1,…\n", out.String())
+}
+
+func TestPrintTable_Nil(t *testing.T) {
+	type row struct {
+		Name string
+	}
+
+	cases := map[string]struct {
+		value    interface{}
+		encoding string
+		opts     []Option
+		expected string
+	}{
+		"nil": {
+			value:    nil,
+			expected: "",
+		},
+		"nil pointer": {
+			value:    (*row)(nil),
+			expected: "NAME\n",
+		},
+		"nil slice": {
+			value:    []row(nil),
+			expected: "NAME\n",
+		},
+		"nil pointer to slice": {
+			value:    (*[]*row)(nil),
+			expected: "NAME\n",
+		},
+		"nil pointer to scalar": {
+			value:    (*int)(nil),
+			expected: "",
+		},
+		"nil pointer to array": {
+			value:    (*[2]row)(nil),
+			expected: "NAME\n",
+		},
+		"no results nil scalar": {
+			value:    (*int)(nil),
+			opts:     []Option{NoResults("No value.")},
+			expected: "No value.\n",
+		},
+		"no results": {
+			value:    []row{},
+			opts:     []Option{NoResults("No users found."), Title("USERS")},
+			expected: "USERS\nNo users found.\n",
+		},
+		"no results nil": {
+			value:    nil,
+			opts:     []Option{NoResults("No users found.")},
+			expected: "No users found.\n",
+		},
+		"no results detail": {
+			value:    (*row)(nil),
+			encoding: "detail",
+			opts:     []Option{NoResults("No users found.")},
+			expected: "No users found.\n",
+		},
+		"no results stream": {
+			value: func() <-chan row {
+				c := make(chan row)
+				close(c)
+				return c
+			}(),
+			opts:     []Option{NoResults("No users found.")},
+			expected: "No users found.\n",
+		},
+		"json": {
+			value:    (*row)(nil),
+			encoding: "json",
+			expected: "null\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			encoding := c.encoding
+			if encoding == "" {
+				encoding = "table"
+			}
+
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter(encoding, c.value, out, c.opts...))
+			assert.Equal(t, c.expected, out.String())
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"reflect"
+	"sync"
+)
+
+// A View describes how values of a type are printed by default. Views let
+// packages ship good defaults for their types so the commands that print them
+// do not have to repeat the same options.
+type View struct {
+	// Columns are the columns that are printed by default (see the Columns
+	// option). If it is empty, all columns are printed.
+	Columns []string
+
+	// Options are applied before the options that are passed to Print so
+	// they can be overridden by each call.
+	Options []Option
+}
+
+// options returns the options of the view.
+func (v View) options() []Option {
+	opts := make([]Option, 0, len(v.Options)+1)
+	if len(v.Columns) > 0 {
+		opts = append(opts, Columns(v.Columns...))
+	}
+	return append(opts, v.Options...)
+}
+
+var (
+	viewsMu sync.RWMutex
+	views   = map[reflect.Type]View{}
+)
+
+// RegisterView registers the default view of the type of the given value.
+// The view is used whenever a value of this type is printed, including
+// pointers to such values as well as slices, arrays and streams of them. A
+// previously registered view of the same type is replaced. Note that streams
+// cannot be printed with a view that sorts, groups or splits the rows unless
+// these options are reset by the call to Print. Usually RegisterView
+// is called from an init function of the package that defines the type:
+//
+//	func init() {
+//		cli.RegisterView(User{}, cli.View{
+//			Columns: []string{"Name", "Email"},
+//			Options: []cli.Option{cli.SortBy("Name", false)},
+//		})
+//	}
+func RegisterView(value interface{}, view View) {
+	viewsMu.Lock()
+	defer viewsMu.Unlock()
+	views[baseType(reflect.TypeOf(value))] = view
+}
+
+// viewOptions returns the options of the view that has been registered for the
+// type of the elements of v.
+func viewOptions(v interface{}) []Option {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil
+	}
+
+	t = baseType(t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Chan:
+		t = baseType(t.Elem())
+	}
+
+	viewsMu.RLock()
+	view, ok := views[t]
+	viewsMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return view.options()
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type viewUser struct {
+	Name  string
+	Email string
+	Age   int
+}
+
+func TestRegisterView(t *testing.T) {
+	RegisterView(viewUser{}, View{
+		Columns: []string{"name", "age"},
+		Options: []Option{SortBy("age", false)},
+	})
+
+	users := []*viewUser{
+		{Name: "bob", Email: "bob@example.com", Age: 42},
+		{Name: "alice", Email: "alice@example.com", Age: 23},
+	}
+
+	cases := map[string]struct {
+		value    interface{}
+		opts     []Option
+		expected string
+	}{
+		"slice": {
+			value:    users,
+			expected: "NAME    AGE\nalice   23\nbob     42\n",
+		},
+		"pointer": {
+			value:    users[0],
+			expected: "NAME    AGE\nbob     42\n",
+		},
+		"stream": {
+			value: func() <-chan viewUser {
+				c := make(chan viewUser, 1)
+				c <- *users[1]
+				close(c)
+				return c
+			}(),
+			opts:     []Option{SortBy("", false)},
+			expected: "NAME    AGE\nalice   23\n",
+		},
+		"overridden": {
+			value:    users,
+			opts:     []Option{Columns("email"), SortBy("name", true)},
+			expected: "EMAIL\nbob@example.com\nalice@example.com\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			require.NoError(t, PrintWriter("table", c.value, out, c.opts...))
+			assert.Equal(t, c.expected, out.String())
+		})
+	}
+}